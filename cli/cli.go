@@ -0,0 +1,148 @@
+// Package cli implements headless subcommands of Kensho, they share backends with the GUI
+// but never open a window so they can run from cron or CI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/profiles"
+)
+
+// secrets are read from environment so they do not end up in shell history or process list
+const (
+	EnvSSHPassword   = "KENSHO_SSH_PASSWORD"
+	EnvKeyPassphrase = "KENSHO_KEY_PASSPHRASE"
+	EnvSudoPassword  = "KENSHO_SUDO_PASSWORD"
+	EnvMnemonic      = "KENSHO_MNEMONIC"
+)
+
+// ErrUsage is returned for invalid arguments, usage is already printed
+var ErrUsage = errors.New("invalid usage")
+
+// Env is shared state of all commands
+type Env struct {
+	HomeFolder string
+	KnownHosts *gssh.KnownHosts
+	Profiles   *profiles.Store
+	Stdin      io.Reader
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, env *Env, args []string) error
+}
+
+var commands = []command{
+	{"connect-test", "check ssh connection and shidai availability", runConnectTest},
+	{"status", "show component versions reported by shidai", runStatus},
+	{"dashboard", "show validator dashboard", runDashboard},
+	{"deploy", "bootstrap sekin if needed and join the network", runDeploy},
+	{"start", "start node components", runStart},
+	{"stop", "stop node components", runStop},
+	{"tx", "run validator tx: pause | unpause | activate | claim-seat", runTx},
+	{"sekaid", "run sekaid command on the node, arguments after -- are passed as is", runSekaid},
+	{"config", "get or set config: get | set", runConfig},
+	{"crawl", "crawl network peers starting from interx of a node", runCrawl},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Run executes subcommand from args and returns process exit code
+func Run(env *Env, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(env.Stdout)
+		return 0
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(env.Stderr, "unknown command <%v>\n\n", args[0])
+		printUsage(env.Stderr)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := cmd.run(ctx, env, args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, ErrUsage):
+		return 2
+	default:
+		fmt.Fprintf(env.Stderr, "ERROR: %v\n", err)
+		return 1
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: kensho [-dev] [command] [flags]\n\nWithout command Kensho starts the GUI.\n\nCommands:\n")
+	list := append([]command(nil), commands...)
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	for _, c := range list {
+		fmt.Fprintf(w, "  %-14v %v\n", c.name, c.usage)
+	}
+	fmt.Fprintf(w, "\nRun \"kensho <command> -h\" for flags of the command.\n")
+	fmt.Fprintf(w, "Secrets are read from %v.\n", strings.Join([]string{EnvSSHPassword, EnvKeyPassphrase, EnvSudoPassword, EnvMnemonic}, ", "))
+}
+
+// creates flag set which prints errors to stderr and returns them instead of exiting
+func newFlagSet(env *Env, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Usage: kensho %v [flags] %v\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parses flags, on error usage is already printed by flag package
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return ErrUsage
+	}
+	return nil
+}
+
+// splits "<subcommand> [flags]", flags before subcommand are only accepted for -h
+func subcommand(fs *flag.FlagSet, args []string, valid ...string) (string, []string, error) {
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if err := parseFlags(fs, args); err != nil {
+			return "", nil, err
+		}
+	}
+	if len(args) == 0 || !slices.Contains(valid, args[0]) {
+		return "", nil, usageError(fs, "one of %v is required", strings.Join(valid, " | "))
+	}
+	return args[0], args[1:], nil
+}
+
+func usageError(fs *flag.FlagSet, format string, a ...any) error {
+	fmt.Fprintf(fs.Output(), format+"\n\n", a...)
+	fs.Usage()
+	return ErrUsage
+}
@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/networkparser"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/types"
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+	"golang.org/x/crypto/ssh"
+)
+
+// flags of commands which connect to a node
+type nodeFlags struct {
+	connect connectFlags
+	output  outputFlags
+}
+
+func newNodeFlagSet(env *Env, name, args string) (*flag.FlagSet, *nodeFlags) {
+	fs := newFlagSet(env, name, args)
+	nf := &nodeFlags{}
+	nf.connect.register(fs)
+	nf.output.register(fs)
+	return fs, nf
+}
+
+// parses flags and connects, client has to be closed by caller
+func (nf *nodeFlags) parseAndDial(env *Env, fs *flag.FlagSet, args []string) (*ssh.Client, profiles.Profile, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return nil, profiles.Profile{}, err
+	}
+	if err := nf.output.validate(fs); err != nil {
+		return nil, profiles.Profile{}, err
+	}
+	if nf.connect.profile == "" && nf.connect.host == "" {
+		return nil, profiles.Profile{}, usageError(fs, "either -profile or -host is required")
+	}
+	return nf.connect.dial(env)
+}
+
+type connectTestResult struct {
+	Host          string `json:"host"`
+	User          string `json:"user"`
+	ServerVersion string `json:"server_version"`
+	ConnectTimeMs int64  `json:"connect_time_ms"`
+	RTTMs         int64  `json:"rtt_ms"`
+	ShidaiPort    int    `json:"shidai_port"`
+	ShidaiOK      bool   `json:"shidai_ok"`
+	ShidaiError   string `json:"shidai_error,omitempty"`
+}
+
+func runConnectTest(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "connect-test", "")
+	start := time.Now()
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result := connectTestResult{
+		Host:          p.Address(),
+		User:          client.User(),
+		ServerVersion: string(client.ServerVersion()),
+		ConnectTimeMs: time.Since(start).Milliseconds(),
+		ShidaiPort:    p.ShidaiPort,
+	}
+	rtt, err := measureRTT(client)
+	if err != nil {
+		return fmt.Errorf("keepalive failed: %w", err)
+	}
+	result.RTTMs = rtt.Milliseconds()
+	if _, err = shidaiclient.New(client, p.ShidaiPort).Status(ctx); err != nil {
+		result.ShidaiError = err.Error()
+	} else {
+		result.ShidaiOK = true
+	}
+
+	err = nf.output.print(env.Stdout, result, func() table {
+		shidaiState := "ok"
+		if !result.ShidaiOK {
+			shidaiState = result.ShidaiError
+		}
+		return fieldsTable(
+			[2]any{"Host", result.Host},
+			[2]any{"User", result.User},
+			[2]any{"Server", result.ServerVersion},
+			[2]any{"Connect time", fmt.Sprintf("%vms", result.ConnectTimeMs)},
+			[2]any{"RTT", fmt.Sprintf("%vms", result.RTTMs)},
+			[2]any{"Shidai", shidaiState},
+		)
+	})
+	if err == nil && !result.ShidaiOK {
+		return fmt.Errorf("shidai is not reachable on port %v", p.ShidaiPort)
+	}
+	return err
+}
+
+func runStatus(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "status", "")
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	status, err := shidaiclient.New(client, p.ShidaiPort).Status(ctx)
+	if err != nil {
+		return err
+	}
+	return nf.output.print(env.Stdout, status, func() table {
+		t := table{header: []string{"COMPONENT", "VERSION", "INFRA"}}
+		t.add("sekai", status.Sekai.Version, status.Sekai.Infra)
+		t.add("interx", status.Interx.Version, status.Interx.Infra)
+		t.add("shidai", status.Shidai.Version, status.Shidai.Infra)
+		t.add("syslog-ng", status.Syslog.Version, status.Syslog.Infra)
+		return t
+	})
+}
+
+func runDashboard(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "dashboard", "")
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	d, err := shidaiclient.New(client, p.ShidaiPort).Dashboard(ctx)
+	if err != nil {
+		return err
+	}
+	return nf.output.print(env.Stdout, d, func() table {
+		return fieldsTable(
+			[2]any{"Chain ID", d.ChainID},
+			[2]any{"Moniker", d.Moniker},
+			[2]any{"Validator status", d.ValidatorStatus},
+			[2]any{"Catching up", d.CatchingUp},
+			[2]any{"Blocks", d.Blocks},
+			[2]any{"Last present block", d.LastProducedBlock},
+			[2]any{"Produced blocks", d.ProducedBlocks},
+			[2]any{"Streak", d.Streak},
+			[2]any{"Rank", d.Top},
+			[2]any{"Mischance", d.Mischance},
+			[2]any{"Mischance confidence", d.MischanceConfidence},
+			[2]any{"Start height", d.StartHeight},
+			[2]any{"Validator address", d.ValidatorAddress},
+			[2]any{"Node ID", d.NodeID},
+			[2]any{"Genesis checksum", d.GenesisChecksum},
+			[2]any{"Seat claim available", d.SeatClaimAvailable},
+			[2]any{"Validators active/paused/inactive/jailed/waiting", fmt.Sprintf("%v/%v/%v/%v/%v",
+				d.ActiveValidators, d.PausedValidators, d.InactiveValidators, d.JailedValidators, d.WaitingValidators)},
+		)
+	})
+}
+
+// runs shidai execute command and prints its output
+func printExecuteResponse(env *Env, nf *nodeFlags, out shidaiclient.ExecuteResponse) error {
+	return nf.output.print(env.Stdout, out, func() table {
+		return table{rows: [][]string{{strings.TrimRight(out.Output, "\n")}}}
+	})
+}
+
+func runStart(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "start", "")
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	out, err := shidaiclient.New(client, p.ShidaiPort).Start(ctx)
+	if err != nil {
+		return err
+	}
+	return printExecuteResponse(env, nf, out)
+}
+
+func runStop(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "stop", "")
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	out, err := shidaiclient.New(client, p.ShidaiPort).Stop(ctx)
+	if err != nil {
+		return err
+	}
+	return printExecuteResponse(env, nf, out)
+}
+
+var txCommands = map[string]types.Cmd{
+	"pause":      types.Pause,
+	"unpause":    types.Unpause,
+	"activate":   types.Activate,
+	"claim-seat": types.ClaimValidatorSeat,
+}
+
+func runTx(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "tx", "pause | unpause | activate | claim-seat")
+	moniker := fs.String("moniker", "", "validator moniker, required for claim-seat")
+	name, args, err := subcommand(fs, args, "pause", "unpause", "activate", "claim-seat")
+	if err != nil {
+		return err
+	}
+	tx := txCommands[name]
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if tx == types.ClaimValidatorSeat && *moniker == "" {
+		return usageError(fs, "-moniker is required for claim-seat")
+	}
+
+	out, err := shidaiclient.New(client, p.ShidaiPort).Tx(ctx, types.ExecSekaiMaintenanceCommands{TX: tx, Moniker: *moniker})
+	if err != nil {
+		return err
+	}
+	return printExecuteResponse(env, nf, out)
+}
+
+func runSekaid(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "sekaid", "-- <sekaid args>")
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if fs.NArg() == 0 {
+		return usageError(fs, "sekaid arguments are required")
+	}
+
+	out, err := shidaiclient.New(client, p.ShidaiPort).Sekaid(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+	return printExecuteResponse(env, nf, out)
+}
+
+func runConfig(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "config", "get | set")
+	configType := fs.String("type", string(shidai.ConfigToml), fmt.Sprintf("config file: %v | %v", shidai.ConfigToml, shidai.AppToml))
+	file := fs.String("file", "-", "toml file to upload with set, - reads stdin")
+	action, args, err := subcommand(fs, args, "get", "set")
+	if err != nil {
+		return err
+	}
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	t := shidai.ConfigType(*configType)
+	if t != shidai.ConfigToml && t != shidai.AppToml {
+		return usageError(fs, "unknown config type <%v>", t)
+	}
+	api := shidaiclient.New(client, p.ShidaiPort)
+
+	if action == "get" {
+		toml, err := api.Config(ctx, t)
+		if err != nil {
+			return err
+		}
+		return nf.output.print(env.Stdout, shidai.ConfigRequest{Type: t, TomlData: toml}, func() table {
+			return table{rows: [][]string{{strings.TrimRight(toml, "\n")}}}
+		})
+	}
+
+	var b []byte
+	if *file == "-" {
+		b, err = io.ReadAll(env.Stdin)
+	} else {
+		b, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read config: %w", err)
+	}
+	if err = api.SetConfig(ctx, t, string(b)); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stderr, "%v updated\n", t)
+	return nil
+}
+
+type crawledNode struct {
+	IP    string   `json:"ip"`
+	ID    string   `json:"id"`
+	Peers int      `json:"peers"`
+	Error []string `json:"errors,omitempty"`
+}
+
+func runCrawl(ctx context.Context, env *Env, args []string) error {
+	fs := newFlagSet(env, "crawl", "")
+	var output outputFlags
+	output.register(fs)
+	ip := fs.String("ip", "", "public address of the first node")
+	port := fs.Int("interx-port", types.DEFAULT_INTERX_PORT, "interx port of crawled nodes")
+	depth := fs.Int("depth", 3, "how many hops from the first node are crawled")
+	all := fs.Bool("all", false, "ignore depth and crawl whole network")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := output.validate(fs); err != nil {
+		return err
+	}
+	if *ip == "" {
+		return usageError(fs, "-ip is required")
+	}
+
+	pool, blacklist, err := networkparser.GetAllNodesV3(ctx, *ip, *port, *depth, *all)
+	if err != nil {
+		return err
+	}
+	nodes := make([]crawledNode, 0, len(pool)+len(blacklist))
+	for _, n := range pool {
+		nodes = append(nodes, crawledNode{IP: n.IP, ID: n.ID, Peers: n.NCPeers})
+	}
+	for _, n := range blacklist {
+		c := crawledNode{IP: n.IP}
+		for _, e := range n.Error {
+			c.Error = append(c.Error, e.Error())
+		}
+		nodes = append(nodes, c)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].IP < nodes[j].IP })
+
+	return output.print(env.Stdout, nodes, func() table {
+		t := table{header: []string{"IP", "NODE ID", "PEERS", "ERROR"}}
+		for _, n := range nodes {
+			t.add(n.IP, n.ID, n.Peers, strings.Join(n.Error, "; "))
+		}
+		return t
+	})
+}
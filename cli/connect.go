@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"github.com/KiraCore/kensho/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// connection flags shared by every command which talks to a node
+type connectFlags struct {
+	profile       string
+	host          string
+	port          int
+	user          string
+	auth          string
+	keyPath       string
+	certPath      string
+	jumpHosts     string
+	shidaiPort    int
+	interxPort    int
+	rpcPort       int
+	acceptNewHost bool
+}
+
+func (c *connectFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.profile, "profile", "", "saved connection profile, flags below override its values")
+	fs.StringVar(&c.host, "host", "", "node address")
+	fs.IntVar(&c.port, "port", 0, "ssh port (default 22)")
+	fs.StringVar(&c.user, "user", "", "ssh user")
+	fs.StringVar(&c.auth, "auth", "", "auth method: password | key_file | agent | certificate (default password, key_file with -key)")
+	fs.StringVar(&c.keyPath, "key", "", "private key file")
+	fs.StringVar(&c.certPath, "cert", "", "user certificate file")
+	fs.StringVar(&c.jumpHosts, "jump", "", "comma separated jump hosts user@host[:port], authenticated with ssh agent")
+	fs.IntVar(&c.shidaiPort, "shidai-port", 0, fmt.Sprintf("shidai port (default %v)", types.DEFAULT_SHIDAI_PORT))
+	fs.IntVar(&c.interxPort, "interx-port", 0, fmt.Sprintf("interx port (default %v)", types.DEFAULT_INTERX_PORT))
+	fs.IntVar(&c.rpcPort, "rpc-port", 0, fmt.Sprintf("sekai rpc port (default %v)", types.DEFAULT_RPC_PORT))
+	fs.BoolVar(&c.acceptNewHost, "accept-new-host-key", false, "pin host key of unknown host, otherwise unknown hosts are rejected")
+}
+
+// merges flags into profile
+func (c *connectFlags) resolve(env *Env) (profiles.Profile, error) {
+	var p profiles.Profile
+	if c.profile != "" {
+		var err error
+		if p, err = env.Profiles.Get(c.profile); err != nil {
+			return p, fmt.Errorf("unable to load profile <%v>: %w", c.profile, err)
+		}
+	}
+	if c.host != "" {
+		p.Host = c.host
+	}
+	if c.port != 0 {
+		p.Port = c.port
+	}
+	if c.user != "" {
+		p.User = c.user
+	}
+	if c.keyPath != "" {
+		p.KeyPath = c.keyPath
+		if c.auth == "" && p.AuthMethod != profiles.AuthCertificate {
+			p.AuthMethod = profiles.AuthKeyFile
+		}
+	}
+	if c.certPath != "" {
+		p.CertPath = c.certPath
+	}
+	if c.auth != "" {
+		p.AuthMethod = profiles.AuthMethod(c.auth)
+	}
+	if c.jumpHosts != "" {
+		p.JumpHosts = nil
+		for _, spec := range strings.Split(c.jumpHosts, ",") {
+			p.JumpHosts = append(p.JumpHosts, profiles.JumpHost{Spec: strings.TrimSpace(spec), AuthMethod: profiles.AuthAgent})
+		}
+	}
+	for _, port := range []struct {
+		flag int
+		dst  *int
+	}{{c.shidaiPort, &p.ShidaiPort}, {c.interxPort, &p.InterxPort}, {c.rpcPort, &p.RPCPort}} {
+		if port.flag != 0 {
+			*port.dst = port.flag
+		}
+	}
+	if p.Name == "" {
+		p.Name = p.Host
+	}
+	p.ApplyDefaults()
+	return p, p.Validate()
+}
+
+// connects to host of the profile, secrets are taken from environment
+func (c *connectFlags) dial(env *Env) (*ssh.Client, profiles.Profile, error) {
+	p, err := c.resolve(env)
+	if err != nil {
+		return nil, p, err
+	}
+
+	hostKeyCallback := env.KnownHosts.HostKeyCallback(func(hostname string, key ssh.PublicKey) bool {
+		if c.acceptNewHost {
+			fmt.Fprintf(env.Stderr, "Pinning host key of <%v>: %v\n", hostname, gssh.Fingerprint(key))
+			return true
+		}
+		fmt.Fprintf(env.Stderr, "Host <%v> is unknown (key %v), run with -accept-new-host-key to pin it\n", hostname, gssh.Fingerprint(key))
+		return false
+	})
+
+	hops, closers, err := buildJumpHosts(p.JumpHosts)
+	defer func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}()
+	if err != nil {
+		return nil, p, err
+	}
+
+	address := p.Address()
+	var client *ssh.Client
+	switch p.AuthMethod {
+	case profiles.AuthPassword:
+		password, ok := os.LookupEnv(EnvSSHPassword)
+		if !ok {
+			return nil, p, fmt.Errorf("password auth requires %v to be set", EnvSSHPassword)
+		}
+		client, err = gssh.MakeSHH_ClientWithPassword(address, p.User, password, hostKeyCallback, hops...)
+	case profiles.AuthAgent:
+		client, err = gssh.MakeSSH_ClientWithAgent(address, p.User, hostKeyCallback, hops...)
+	case profiles.AuthKeyFile, profiles.AuthCertificate:
+		var key []byte
+		if key, err = os.ReadFile(p.KeyPath); err != nil {
+			return nil, p, fmt.Errorf("unable to read private key: %w", err)
+		}
+		// passphrase is only used for encrypted keys, x/crypto rejects it for plain ones
+		var passphrase []byte
+		var needed bool
+		if needed, err = gssh.CheckIfPassphraseNeeded(key); err != nil {
+			return nil, p, fmt.Errorf("unable to parse private key <%v>: %w", p.KeyPath, err)
+		}
+		if needed {
+			if passphrase = []byte(os.Getenv(EnvKeyPassphrase)); len(passphrase) == 0 {
+				return nil, p, fmt.Errorf("private key <%v> is encrypted, set %v to its passphrase", p.KeyPath, EnvKeyPassphrase)
+			}
+		}
+		if p.AuthMethod == profiles.AuthCertificate {
+			var cert []byte
+			if cert, err = os.ReadFile(p.CertPath); err != nil {
+				return nil, p, fmt.Errorf("unable to read certificate: %w", err)
+			}
+			client, err = gssh.MakeSSH_ClientWithCertificate(address, p.User, key, passphrase, cert, hostKeyCallback, hops...)
+		} else if needed {
+			client, err = gssh.MakeSSH_ClientWithPrivKeyAndPassphrase(address, p.User, key, passphrase, hostKeyCallback, hops...)
+		} else {
+			client, err = gssh.MakeSSH_ClientWithPrivKey(address, p.User, key, hostKeyCallback, hops...)
+		}
+	}
+	if err != nil {
+		return nil, p, fmt.Errorf("unable to connect to <%v>: %w", address, err)
+	}
+	return client, p, nil
+}
+
+// password hops cannot be asked for in headless mode, only agent and key files are supported
+func buildJumpHosts(list []profiles.JumpHost) ([]gssh.JumpHost, []io.Closer, error) {
+	var closers []io.Closer
+	out := make([]gssh.JumpHost, 0, len(list))
+	for _, j := range list {
+		user, address, err := gssh.ParseJumpHostSpec(j.Spec)
+		if err != nil {
+			return nil, closers, err
+		}
+		var auth ssh.AuthMethod
+		switch j.AuthMethod {
+		case profiles.AuthKeyFile:
+			var key []byte
+			if key, err = os.ReadFile(j.KeyPath); err == nil {
+				auth, err = gssh.NewPrivKeyAuth(key, []byte(os.Getenv(EnvKeyPassphrase)))
+			}
+		case profiles.AuthAgent:
+			var closer io.Closer
+			auth, closer, err = gssh.NewAgentAuth()
+			if closer != nil {
+				closers = append(closers, closer)
+			}
+		default:
+			err = fmt.Errorf("<%v> auth is not supported in headless mode", j.AuthMethod)
+		}
+		if err != nil {
+			return nil, closers, fmt.Errorf("jump host <%v>: %w", address, err)
+		}
+		out = append(out, gssh.JumpHost{Address: address, User: user, Auth: []ssh.AuthMethod{auth}})
+	}
+	return out, closers, nil
+}
+
+// measures ssh round trip with keepalive request
+func measureRTT(client *ssh.Client) (time.Duration, error) {
+	start := time.Now()
+	_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+	return time.Since(start), err
+}
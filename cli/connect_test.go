@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/gssh/sshtest"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"golang.org/x/crypto/ssh"
+)
+
+// writes ed25519 key in OpenSSH format, encrypted when passphrase is not empty
+func writeTestKey(t *testing.T, dir, name, passphrase string) (string, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err = os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, sshPub
+}
+
+func TestConnectFlagsDialKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	plainKey, plainPub := writeTestKey(t, dir, "plain", "")
+	encryptedKey, encryptedPub := writeTestKey(t, dir, "encrypted", "secret")
+
+	address, _ := sshtest.NewServer(t, &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), plainPub.Marshal()) || bytes.Equal(key.Marshal(), encryptedPub.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	})
+	host, portString, _ := net.SplitHostPort(address)
+	port, _ := strconv.Atoi(portString)
+
+	knownHosts, err := gssh.NewKnownHosts(filepath.Join(dir, "known_hosts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Env{KnownHosts: knownHosts, Stdout: io.Discard, Stderr: io.Discard}
+
+	tests := []struct {
+		name       string
+		key        string
+		passphrase string
+		wantErr    string
+	}{
+		{name: "unencrypted key", key: plainKey},
+		{name: "unencrypted key ignores passphrase", key: plainKey, passphrase: "unused"},
+		{name: "encrypted key", key: encryptedKey, passphrase: "secret"},
+		{name: "encrypted key without passphrase", key: encryptedKey, wantErr: EnvKeyPassphrase},
+		{name: "encrypted key with wrong passphrase", key: encryptedKey, passphrase: "wrong", wantErr: "unable to connect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvKeyPassphrase, tt.passphrase)
+			flags := &connectFlags{host: host, port: port, user: "kira", keyPath: tt.key, acceptNewHost: true}
+			client, p, err := flags.dial(env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.Close()
+			if p.AuthMethod != profiles.AuthKeyFile {
+				t.Fatalf("unexpected auth method %v", p.AuthMethod)
+			}
+		})
+	}
+}
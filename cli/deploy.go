@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KiraCore/kensho/helper/deployplan"
+	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/types"
+)
+
+func runDeploy(ctx context.Context, env *Env, args []string) error {
+	fs, nf := newNodeFlagSet(env, "deploy", "")
+	trustedIP := fs.String("trusted-ip", "", "address of trusted node to join")
+	trustedRPC := fs.Int("trusted-rpc-port", types.DEFAULT_RPC_PORT, "sekai rpc port of trusted node")
+	trustedInterx := fs.Int("trusted-interx-port", types.DEFAULT_INTERX_PORT, "interx port of trusted node")
+	p2pPort := fs.Int("p2p-port", types.DEFAULT_P2P_PORT, "sekai p2p port of trusted node")
+	local := fs.Bool("local", false, "trusted node is in local network")
+	dryRun := fs.Bool("dry-run", false, "print every remote command and request of the deployment without running it")
+	mnemonicFile := fs.String("mnemonic-file", "", fmt.Sprintf("file with validator mnemonic, %v is used when empty", EnvMnemonic))
+
+	client, p, err := nf.parseAndDial(env, fs, args)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if !httph.ValidateIP(*trustedIP) {
+		return usageError(fs, "-trusted-ip <%v> is not valid", *trustedIP)
+	}
+	for _, port := range []int{*trustedRPC, *trustedInterx, *p2pPort} {
+		if !httph.ValidatePortRange(strconv.Itoa(port)) {
+			return usageError(fs, "port <%v> is not valid", port)
+		}
+	}
+	mnemonic := os.Getenv(EnvMnemonic)
+	if *mnemonicFile != "" {
+		b, err := os.ReadFile(*mnemonicFile)
+		if err != nil {
+			return fmt.Errorf("unable to read mnemonic: %w", err)
+		}
+		mnemonic = string(b)
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if mnemonic == "" {
+		return usageError(fs, "mnemonic is required, use -mnemonic-file or %v", EnvMnemonic)
+	}
+
+	api := shidaiclient.New(client, p.ShidaiPort)
+	// sekin is bootstrapped only when shidai is not running yet, same as in the GUI
+	_, statusErr := api.Status(ctx)
+	if statusErr != nil {
+		fmt.Fprintf(env.Stderr, "Shidai is not available (%v), sekin will be bootstrapped\n", statusErr)
+	}
+	plan, vars := deployplan.NewJoinPlan(deployplan.JoinParams{
+		TrustedIP:  *trustedIP,
+		RPCPort:    *trustedRPC,
+		InterxPort: *trustedInterx,
+		P2PPort:    *p2pPort,
+		Local:      *local,
+		Mnemonic:   mnemonic,
+		User:       client.User(),
+		Bootstrap:  statusErr != nil,
+	})
+
+	if *dryRun {
+		preview := plan.DryRun(vars)
+		return nf.output.print(env.Stdout, preview, func() table {
+			return table{rows: [][]string{{strings.TrimRight(deployplan.FormatDryRun(plan.Name, preview), "\n")}}}
+		})
+	}
+
+	e := deployplan.NewExecution(plan, vars, &deployplan.Env{
+		Client:       client,
+		Shidai:       api,
+		SudoPassword: os.Getenv(EnvSudoPassword),
+		Output:       env.Stderr,
+	})
+	e.OnProgress = func(pr deployplan.Progress) {
+		fmt.Fprintf(env.Stderr, "[%v/%v] %v: %v\n", pr.Step+1, len(plan.Steps), pr.Name, pr.State)
+	}
+	if err = e.Run(ctx); err != nil {
+		return err
+	}
+	out, _ := vars.Get("join_output")
+	return printExecuteResponse(env, nf, shidaiclient.ExecuteResponse{Output: out})
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+type outputFlags struct {
+	format string
+}
+
+func (o *outputFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "o", string(outputTable), "output format: table | json")
+}
+
+func (o *outputFlags) validate(fs *flag.FlagSet) error {
+	switch outputFormat(o.format) {
+	case outputTable, outputJSON:
+		return nil
+	}
+	return usageError(fs, "unknown output format <%v>", o.format)
+}
+
+// table is rendered as aligned columns or as list of objects keyed by header in json
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// prints value as json or table returned by toTable
+func (o *outputFlags) print(w io.Writer, value any, toTable func() table) error {
+	if outputFormat(o.format) == outputJSON {
+		return writeJSON(w, value)
+	}
+	return toTable().write(w)
+}
+
+func writeJSON(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+func (t table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(t.header) > 0 {
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// key value table of named fields
+func fieldsTable(fields ...[2]any) table {
+	t := table{header: []string{"FIELD", "VALUE"}}
+	for _, f := range fields {
+		t.add(f[0], f[1])
+	}
+	return t
+}
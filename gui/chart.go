@@ -0,0 +1,176 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/metrics"
+)
+
+// lineChart draws points as a polyline scaled to the widget size, with min/max values and time range as labels
+type lineChart struct {
+	widget.BaseWidget
+
+	mu     sync.Mutex
+	points []metrics.Point
+}
+
+func newLineChart() *lineChart {
+	c := &lineChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *lineChart) SetPoints(points []metrics.Point) {
+	c.mu.Lock()
+	c.points = points
+	c.mu.Unlock()
+	c.Refresh()
+}
+
+func (c *lineChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &lineChartRenderer{
+		chart:      c,
+		background: canvas.NewRectangle(theme.InputBackgroundColor()),
+		maxLabel:   canvas.NewText("", theme.ForegroundColor()),
+		minLabel:   canvas.NewText("", theme.ForegroundColor()),
+		rangeLabel: canvas.NewText("", theme.PlaceHolderColor()),
+	}
+	for _, t := range []*canvas.Text{r.maxLabel, r.minLabel, r.rangeLabel} {
+		t.TextSize = theme.CaptionTextSize()
+	}
+	r.Refresh()
+	return r
+}
+
+type lineChartRenderer struct {
+	chart      *lineChart
+	background *canvas.Rectangle
+	maxLabel   *canvas.Text
+	minLabel   *canvas.Text
+	rangeLabel *canvas.Text
+	lines      []*canvas.Line
+	size       fyne.Size
+}
+
+func (r *lineChartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.background.Resize(size)
+	r.Refresh()
+}
+
+func (r *lineChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, 120)
+}
+
+func (r *lineChartRenderer) Refresh() {
+	r.chart.mu.Lock()
+	points := r.chart.points
+	r.chart.mu.Unlock()
+
+	r.background.FillColor = theme.InputBackgroundColor()
+	r.background.Refresh()
+
+	pad := theme.Padding()
+	textHeight := r.maxLabel.MinSize().Height
+	r.maxLabel.Move(fyne.NewPos(pad, pad))
+	r.minLabel.Move(fyne.NewPos(pad, r.size.Height-textHeight-pad))
+
+	if len(points) == 0 {
+		r.maxLabel.Text, r.minLabel.Text, r.rangeLabel.Text = "No data", "", ""
+		r.lines = nil
+		r.refreshText()
+		return
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		lo, hi = math.Min(lo, p.Value), math.Max(hi, p.Value)
+	}
+	if hi == lo {
+		// flat line is drawn in the middle
+		hi, lo = hi+1, lo-1
+	}
+	start, end := points[0].Time, points[len(points)-1].Time
+	span := end.Sub(start)
+
+	r.maxLabel.Text = formatChartValue(hi)
+	r.minLabel.Text = formatChartValue(lo)
+	r.rangeLabel.Text = fmt.Sprintf("%v - %v", start.Local().Format(time.DateTime), end.Local().Format(time.DateTime))
+	rangeSize := r.rangeLabel.MinSize()
+	r.rangeLabel.Move(fyne.NewPos(r.size.Width-rangeSize.Width-pad, r.size.Height-rangeSize.Height-pad))
+
+	// plot area leaves room for labels on top and bottom
+	top, bottom := textHeight+2*pad, r.size.Height-textHeight-2*pad
+	left, right := pad, r.size.Width-pad
+	pos := func(p metrics.Point) fyne.Position {
+		x := left
+		if span > 0 {
+			x += float32(p.Time.Sub(start).Seconds()/span.Seconds()) * (right - left)
+		}
+		y := bottom - float32((p.Value-lo)/(hi-lo))*(bottom-top)
+		return fyne.NewPos(x, y)
+	}
+
+	lineColor := theme.PrimaryColor()
+	lines := make([]*canvas.Line, 0, len(points))
+	for i := 1; i < len(points); i++ {
+		l := r.line(i-1, lineColor)
+		l.Position1, l.Position2 = pos(points[i-1]), pos(points[i])
+		lines = append(lines, l)
+	}
+	if len(points) == 1 {
+		l := r.line(0, lineColor)
+		p := pos(points[0])
+		l.Position1, l.Position2 = p, fyne.NewPos(right, p.Y)
+		lines = append(lines, l)
+	}
+	r.lines = lines
+	r.refreshText()
+	for _, l := range r.lines {
+		l.Refresh()
+	}
+}
+
+// reuses existing lines so refresh does not allocate on every tick
+func (r *lineChartRenderer) line(i int, c color.Color) *canvas.Line {
+	var l *canvas.Line
+	if i < len(r.lines) {
+		l = r.lines[i]
+	} else {
+		l = canvas.NewLine(c)
+		l.StrokeWidth = 2
+	}
+	l.StrokeColor = c
+	return l
+}
+
+func (r *lineChartRenderer) refreshText() {
+	for _, t := range []*canvas.Text{r.maxLabel, r.minLabel, r.rangeLabel} {
+		t.Refresh()
+	}
+}
+
+func (r *lineChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background}
+	for _, l := range r.lines {
+		objects = append(objects, l)
+	}
+	return append(objects, r.maxLabel, r.minLabel, r.rangeLabel)
+}
+
+func (r *lineChartRenderer) Destroy() {}
+
+func formatChartValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
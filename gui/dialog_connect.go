@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -14,6 +16,9 @@ import (
 	"fyne.io/fyne/v2/widget"
 	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
 	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"github.com/KiraCore/kensho/types"
 	"github.com/fyne-io/terminal"
 	"golang.org/x/crypto/ssh"
 )
@@ -34,6 +39,7 @@ func (g *Gui) ShowConnect() {
 		passphraseEntry := widget.NewPasswordEntry()
 		passphraseEntry.Hide()
 		var privKeyState bool
+		authMethod := profiles.AuthPassword
 		var passphraseState bool
 		var rawKeyState bool
 		portEntry.PlaceHolder = "22"
@@ -129,16 +135,76 @@ func (g *Gui) ShowConnect() {
 			container.NewHBox(passphraseCheck, rawKeyCheck),
 		)
 
-		privKeyCheck := widget.NewCheck("Join with private key", func(b bool) {
-			privKeyState = b
-			if b {
+		certPathEntry := widget.NewEntry()
+		certPathEntry.PlaceHolder = "path to your certificate (*-cert.pub)"
+		certFileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if reader == nil {
+				return
+			}
+			certPathEntry.SetText(reader.URI().Path())
+		}, g.Window)
+		certBox := container.NewBorder(
+			widget.NewLabel("Select user certificate"),
+			nil, nil,
+			widget.NewButtonWithIcon("", theme.FileIcon(), func() { certFileDialog.Show() }),
+			certPathEntry,
+		)
+
+		agentInfoLabel := widget.NewLabel("Keys held by ssh-agent (SSH_AUTH_SOCK) will be used, including hardware keys")
+		agentInfoLabel.Wrapping = fyne.TextWrapWord
+		agentKeysButton := widget.NewButton("Show agent keys", func() {
+			keys, err := gssh.ListAgentKeys()
+			if err != nil {
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				return
+			}
+			if len(keys) == 0 {
+				showInfoDialog(g, "ssh-agent", "ssh-agent holds no keys, add them with ssh-add")
+				return
+			}
+			showInfoDialog(g, "ssh-agent", strings.Join(keys, "\n"))
+		})
+		agentBoxEntry := container.NewVBox(agentInfoLabel, agentKeysButton)
+
+		authMethodNames := []string{"Password", "Private key", "SSH agent", "Certificate"}
+		authMethods := map[string]profiles.AuthMethod{
+			"Password":    profiles.AuthPassword,
+			"Private key": profiles.AuthKeyFile,
+			"SSH agent":   profiles.AuthAgent,
+			"Certificate": profiles.AuthCertificate,
+		}
+		authMethodSelect := widget.NewSelect(authMethodNames, func(name string) {
+			authMethod = authMethods[name]
+			privKeyState = authMethod == profiles.AuthKeyFile || authMethod == profiles.AuthCertificate
+			switch authMethod {
+			case profiles.AuthKeyFile:
 				keyEntryBox.Objects = []fyne.CanvasObject{privKeyBoxEntry}
-			} else {
+			case profiles.AuthCertificate:
+				rawKeyCheck.SetChecked(false)
+				keyEntryBox.Objects = []fyne.CanvasObject{container.NewVBox(privKeyBoxEntry, certBox)}
+			case profiles.AuthAgent:
+				keyEntryBox.Objects = []fyne.CanvasObject{agentBoxEntry}
+			default:
 				keyEntryBox.Objects = []fyne.CanvasObject{passwordBoxEntry}
 			}
+			keyEntryBox.Refresh()
 		})
+		setAuthMethod := func(m profiles.AuthMethod) {
+			for name, method := range authMethods {
+				if method == m {
+					authMethodSelect.SetSelected(name)
+					return
+				}
+			}
+		}
+		setAuthMethod(profiles.AuthPassword)
+		authMethodBox := container.NewBorder(nil, nil, widget.NewLabel("Auth:"), nil, authMethodSelect)
 
 		rawKeyCheck.OnChanged = func(b bool) {
+			if b && authMethod == profiles.AuthCertificate {
+				rawKeyCheck.SetChecked(false)
+				return
+			}
 			rawKeyState = b
 			if b {
 				privKeyBoxEntry.Objects[0] = rawPrivKeyBox
@@ -147,6 +213,174 @@ func (g *Gui) ShowConnect() {
 			}
 		}
 
+		shidaiPortEntry := widget.NewEntry()
+		shidaiPortEntry.SetPlaceHolder(strconv.Itoa(types.DEFAULT_SHIDAI_PORT))
+		interxPortEntry := widget.NewEntry()
+		interxPortEntry.SetPlaceHolder(strconv.Itoa(types.DEFAULT_INTERX_PORT))
+		rpcPortEntry := widget.NewEntry()
+		rpcPortEntry.SetPlaceHolder(strconv.Itoa(types.DEFAULT_RPC_PORT))
+		keepAliveIntervalEntry := widget.NewEntry()
+		keepAliveIntervalEntry.SetPlaceHolder(strconv.Itoa(types.DEFAULT_KEEPALIVE_INTERVAL))
+		keepAliveMissesEntry := widget.NewEntry()
+		keepAliveMissesEntry.SetPlaceHolder(strconv.Itoa(types.DEFAULT_KEEPALIVE_MISSES))
+		jumpHosts := newJumpHostsEditor()
+		advancedAccordion := widget.NewAccordion(
+			widget.NewAccordionItem("Node ports", widget.NewForm(
+				widget.NewFormItem("Shidai", shidaiPortEntry),
+				widget.NewFormItem("Interx", interxPortEntry),
+				widget.NewFormItem("RPC", rpcPortEntry),
+			)),
+			widget.NewAccordionItem("Jump hosts", jumpHosts.CanvasObject()),
+			widget.NewAccordionItem("Keepalive", widget.NewForm(
+				widget.NewFormItem("Interval (sec)", keepAliveIntervalEntry),
+				widget.NewFormItem("Max misses", keepAliveMissesEntry),
+			)),
+		)
+
+		// profiles block
+		profileNameBinding := binding.NewString()
+		profileSelect := widget.NewSelect([]string{}, func(string) {})
+		profileSelect.PlaceHolder = "Saved profiles"
+		duplicateProfileButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {})
+		deleteProfileButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {})
+
+		reloadProfiles := func(selected string) {
+			names, err := getProfileNames(g)
+			if err != nil {
+				errorLabel.SetText(fmt.Sprintf("ERROR: %s", err.Error()))
+				return
+			}
+			profileSelect.Options = names
+			profileSelect.Refresh()
+			if selected == "" {
+				profileSelect.ClearSelected()
+			} else {
+				profileSelect.SetSelected(selected)
+			}
+		}
+
+		loadProfile := func(p profiles.Profile) {
+			ipEntry.SetText(p.Host)
+			portEntry.SetText(strconv.Itoa(p.Port))
+			userEntry.SetText(p.User)
+			passwordEntry.SetText("")
+			passphraseEntry.SetText("")
+			setAuthMethod(p.AuthMethod)
+			if p.AuthMethod == profiles.AuthKeyFile || p.AuthMethod == profiles.AuthCertificate {
+				rawKeyCheck.SetChecked(false)
+				keyPathEntry.SetText(p.KeyPath)
+				certPathEntry.SetText(p.CertPath)
+			}
+			shidaiPortEntry.SetText(strconv.Itoa(p.ShidaiPort))
+			interxPortEntry.SetText(strconv.Itoa(p.InterxPort))
+			rpcPortEntry.SetText(strconv.Itoa(p.RPCPort))
+			jumpHosts.Load(p.JumpHosts)
+			keepAliveIntervalEntry.SetText(strconv.Itoa(p.KeepAliveInterval))
+			keepAliveMissesEntry.SetText(strconv.Itoa(p.KeepAliveMisses))
+		}
+
+		formToProfile := func(name string) (profiles.Profile, error) {
+			var err error
+			p := profiles.Profile{
+				Name:       name,
+				Host:       strings.TrimSpace(ipEntry.Text),
+				User:       strings.TrimSpace(userEntry.Text),
+				AuthMethod: authMethod,
+			}
+			if privKeyState {
+				if rawKeyState {
+					return p, fmt.Errorf("raw keys cannot be saved in profile, select key file instead")
+				}
+				p.KeyPath = strings.TrimSpace(keyPathEntry.Text)
+			}
+			if authMethod == profiles.AuthCertificate {
+				p.CertPath = strings.TrimSpace(certPathEntry.Text)
+			}
+			p.JumpHosts = jumpHosts.Profiles()
+			if p.Port, err = parsePortEntry(portEntry, types.DEFAULT_SSH_PORT); err != nil {
+				return p, err
+			}
+			if p.ShidaiPort, err = parsePortEntry(shidaiPortEntry, types.DEFAULT_SHIDAI_PORT); err != nil {
+				return p, err
+			}
+			if p.InterxPort, err = parsePortEntry(interxPortEntry, types.DEFAULT_INTERX_PORT); err != nil {
+				return p, err
+			}
+			if p.RPCPort, err = parsePortEntry(rpcPortEntry, types.DEFAULT_RPC_PORT); err != nil {
+				return p, err
+			}
+			if p.KeepAliveInterval, err = parsePositiveIntEntry(keepAliveIntervalEntry, types.DEFAULT_KEEPALIVE_INTERVAL); err != nil {
+				return p, err
+			}
+			if p.KeepAliveMisses, err = parsePositiveIntEntry(keepAliveMissesEntry, types.DEFAULT_KEEPALIVE_MISSES); err != nil {
+				return p, err
+			}
+			return p, nil
+		}
+
+		profileSelect.OnChanged = func(name string) {
+			if name == "" {
+				return
+			}
+			p, err := g.Profiles.Get(name)
+			if err != nil {
+				errorLabel.SetText(fmt.Sprintf("ERROR: %s", err.Error()))
+				return
+			}
+			profileNameBinding.Set(p.Name)
+			loadProfile(p)
+		}
+
+		saveProfileAction := binding.NewDataListener(func() {
+			name, _ := profileNameBinding.Get()
+			p, err := formToProfile(name)
+			if err == nil {
+				err = g.Profiles.Save(p)
+			}
+			if err != nil {
+				g.showErrorDialog(fmt.Errorf("unable to save profile: %w", err), binding.NewDataListener(func() {}))
+				return
+			}
+			reloadProfiles(name)
+		})
+		saveProfileButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+			showProfileNameDialog(g, profileNameBinding, saveProfileAction)
+		})
+
+		duplicateProfileButton.OnTapped = func() {
+			if profileSelect.Selected == "" {
+				return
+			}
+			p, err := g.Profiles.Duplicate(profileSelect.Selected)
+			if err != nil {
+				g.showErrorDialog(fmt.Errorf("unable to duplicate profile: %w", err), binding.NewDataListener(func() {}))
+				return
+			}
+			reloadProfiles(p.Name)
+		}
+
+		deleteProfileButton.OnTapped = func() {
+			name := profileSelect.Selected
+			if name == "" {
+				return
+			}
+			showWarningMessageWithConfirmation(g, fmt.Sprintf("Delete profile <%v>?", name), binding.NewDataListener(func() {
+				err := g.Profiles.Delete(name)
+				if err != nil {
+					g.showErrorDialog(fmt.Errorf("unable to delete profile: %w", err), binding.NewDataListener(func() {}))
+					return
+				}
+				profileNameBinding.Set("")
+				reloadProfiles("")
+			}))
+		}
+
+		profilesBox := container.NewBorder(nil, nil, nil,
+			container.NewHBox(saveProfileButton, duplicateProfileButton, deleteProfileButton),
+			profileSelect,
+		)
+		reloadProfiles("")
+
 		errorLabel.Wrapping = 2
 
 		submitFunc := func() {
@@ -160,12 +394,36 @@ func (g *Gui) ShowConnect() {
 				port = strings.TrimSpace(portEntry.Text)
 			}
 			address := fmt.Sprintf("%v:%v", ip, (port))
+			formValues, err := formToProfile("")
+			if err != nil {
+				errorLabel.SetText(fmt.Sprintf("ERROR: %s", err.Error()))
+				g.WaitDialog.HideWaitDialog()
+				return
+			}
+			hostKeyCallback := g.KnownHosts.HostKeyCallback(g.confirmHostKey)
+			user := userEntry.Text
+
+			// every dial builds jump host auth again, agent connections are only needed during handshake
+			withJumpHosts := func(connect func(hops []gssh.JumpHost) (*ssh.Client, error)) func() (*ssh.Client, error) {
+				return func() (*ssh.Client, error) {
+					hops, hopClosers, err := jumpHosts.Build()
+					if err != nil {
+						return nil, err
+					}
+					defer func() {
+						for _, c := range hopClosers {
+							c.Close()
+						}
+					}()
+					return connect(hops)
+				}
+			}
 
+			// dial is kept by the connection for reconnecting, so all form values are captured here
+			var dial func() (*ssh.Client, error)
 			if privKeyState {
 				var b []byte
-				var c *ssh.Client
-
-				g.sshClient, err = func() (*ssh.Client, error) {
+				dial, err = func() (func() (*ssh.Client, error), error) {
 					log.Println("Raw key state: ", rawKeyState)
 					if rawKeyState {
 						b = []byte(rawKeyEntry.Text)
@@ -188,46 +446,84 @@ func (g *Gui) ShowConnect() {
 							passphraseEntry.SetValidationError(fmt.Errorf("passphrase required"))
 							passphraseCheck.SetChecked(true)
 						}
+					}
+					var passphrase []byte
+					if check {
+						passphrase = []byte(passphraseEntry.Text)
+					}
 
-						c, err = gssh.MakeSSH_ClientWithPrivKeyAndPassphrase(address, userEntry.Text, b, []byte(passphraseEntry.Text))
+					if authMethod == profiles.AuthCertificate {
+						certBytes, err := os.ReadFile(certPathEntry.Text)
 						if err != nil {
-							log.Printf("error when creating ssh client: %v", err.Error())
 							return nil, err
 						}
-					} else {
-						if !passphraseEntry.Hidden {
-							passphraseCheck.SetChecked(false)
+						return withJumpHosts(func(hops []gssh.JumpHost) (*ssh.Client, error) {
+							return gssh.MakeSSH_ClientWithCertificate(address, user, b, passphrase, certBytes, hostKeyCallback, hops...)
+						}), nil
+					}
 
-						}
-						c, err = gssh.MakeSSH_ClientWithPrivKey(address, userEntry.Text, b)
-						if err != nil {
-							return nil, err
-						}
+					if check {
+						return withJumpHosts(func(hops []gssh.JumpHost) (*ssh.Client, error) {
+							return gssh.MakeSSH_ClientWithPrivKeyAndPassphrase(address, user, b, passphrase, hostKeyCallback, hops...)
+						}), nil
 					}
-					return c, nil
+					if !passphraseEntry.Hidden {
+						passphraseCheck.SetChecked(false)
+					}
+					return withJumpHosts(func(hops []gssh.JumpHost) (*ssh.Client, error) {
+						return gssh.MakeSSH_ClientWithPrivKey(address, user, b, hostKeyCallback, hops...)
+					}), nil
 				}()
+			} else if authMethod == profiles.AuthAgent {
+				dial = withJumpHosts(func(hops []gssh.JumpHost) (*ssh.Client, error) {
+					return gssh.MakeSSH_ClientWithAgent(address, user, hostKeyCallback, hops...)
+				})
 			} else {
-				g.sshClient, err = gssh.MakeSHH_ClientWithPassword(address, userEntry.Text, passwordEntry.Text)
+				password := passwordEntry.Text
+				dial = withJumpHosts(func(hops []gssh.JumpHost) (*ssh.Client, error) {
+					return gssh.MakeSHH_ClientWithPassword(address, user, password, hostKeyCallback, hops...)
+				})
+			}
+
+			var client *ssh.Client
+			if err == nil {
+				client, err = dial()
 			}
 			if err != nil {
 				log.Println("ERROR submitting:", err.Error())
 				errorLabel.SetText(fmt.Sprintf("ERROR: %s", err.Error()))
 				g.showErrorDialog(err, binding.NewDataListener(func() {}))
 			} else {
-				err := TryToRunSSHSessionForTerminal(g)
+				connectionName := profileSelect.Selected
+				if connectionName == "" {
+					connectionName = fmt.Sprintf("%v@%v", client.User(), address)
+				}
+				conn := &HostConnection{
+					Name:      connectionName,
+					sshClient: client,
+					dial:      dial,
+					keepAlive: gssh.KeepAliveConfig{
+						Interval:  time.Duration(formValues.KeepAliveInterval) * time.Second,
+						MaxMisses: formValues.KeepAliveMisses,
+					},
+					Host: &Host{
+						IP:         ip,
+						ShidaiPort: formValues.ShidaiPort,
+						InterxPort: formValues.InterxPort,
+						RPCPort:    formValues.RPCPort,
+					},
+				}
+				if authMethod == profiles.AuthPassword {
+					password := passwordEntry.Text
+					conn.Host.UserPassword = &password
+				}
+				err := TryToRunSSHSessionForTerminal(conn)
 				if err != nil {
 					g.showErrorDialog(fmt.Errorf("unable to create terminal instance, disabling terminal: %v", err.Error()), binding.NewDataListener(func() {}))
-					g.Terminal.Term = terminal.New()
+					conn.Terminal.Term = terminal.New()
 
 				}
-				g.Host = &Host{
-					IP: ip,
-				}
-				if !privKeyState {
-					g.Host.UserPassword = &passwordEntry.Text
-				}
-				go g.sshAliveTracker()
-				g.ConnectionStatusBinding.Set(true)
+				g.addHostConnection(conn)
 				wizard.Hide()
 			}
 			defer g.WaitDialog.HideWaitDialog()
@@ -239,8 +535,9 @@ func (g *Gui) ShowConnect() {
 			userEntry.Text = "d"
 			passwordEntry.Text = "d"
 			passphraseCheck.SetChecked(false)
+			setAuthMethod(profiles.AuthPassword)
 
-			submitFunc()
+			go submitFunc()
 		})
 
 		if !g.DeveloperMode {
@@ -250,20 +547,25 @@ func (g *Gui) ShowConnect() {
 
 		///
 
-		ipEntry.OnSubmitted = func(s string) { submitFunc() }
-		userEntry.OnSubmitted = func(s string) { submitFunc() }
-		passwordEntry.OnSubmitted = func(s string) { submitFunc() }
-		connectButton := widget.NewButton("Connect to remote host", func() { submitFunc() })
+		// submitting in separate goroutine, host key confirmation dialog waits for user input
+		ipEntry.OnSubmitted = func(s string) { go submitFunc() }
+		userEntry.OnSubmitted = func(s string) { go submitFunc() }
+		passwordEntry.OnSubmitted = func(s string) { go submitFunc() }
+		connectButton := widget.NewButton("Connect to remote host", func() { go submitFunc() })
+		knownHostsButton := widget.NewButtonWithIcon("Known hosts", theme.ListIcon(), func() { showKnownHostsDialog(g) })
 
 		logging := container.NewBorder(
 			container.NewVBox(
+				profilesBox,
 				widget.NewLabel("IP and Port"),
 				addressBoxEntry,
 				widget.NewLabel("User"),
 				userEntry,
+				authMethodBox,
 				keyEntryBox,
-				privKeyCheck,
+				advancedAccordion,
 				connectButton,
+				knownHostsButton,
 				testButton,
 			),
 			nil, nil, nil,
@@ -274,21 +576,32 @@ func (g *Gui) ShowConnect() {
 
 	wizard = dialogWizard.NewWizard("Create ssh connection", join())
 	wizard.Show(g.Window)
-	wizard.Resize(fyne.NewSize(350, 540))
+	wizard.Resize(fyne.NewSize(400, 640))
 }
 
-func (g *Gui) sshAliveTracker() {
-	errorDoneBinding := binding.NewDataListener(func() {
-		// g.ShowConnect()
-	})
-	g.ConnectionCount++
-
-	err := g.sshClient.Wait()
-	if err != nil {
-		log.Printf("SSH was interrupted: %v", err.Error())
-		g.ConnectionStatusBinding.Set(false)
-
-		g.showErrorDialog(fmt.Errorf("SSH connection was disconnected, reason: %v", err.Error()), errorDoneBinding)
+// returns port from entry or default value if entry is empty
+func parsePortEntry(e *widget.Entry, defaultPort int) (int, error) {
+	text := strings.TrimSpace(e.Text)
+	if text == "" {
+		return defaultPort, nil
+	}
+	if !httph.ValidatePortRange(text) {
+		e.SetValidationError(fmt.Errorf("invalid port"))
+		return 0, fmt.Errorf("port <%v> is not valid", text)
 	}
+	return strconv.Atoi(text)
+}
 
+// returns positive number from entry or default value if entry is empty
+func parsePositiveIntEntry(e *widget.Entry, defaultValue int) (int, error) {
+	text := strings.TrimSpace(e.Text)
+	if text == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil || v < 1 {
+		e.SetValidationError(fmt.Errorf("invalid value"))
+		return 0, fmt.Errorf("<%v> is not a positive number", text)
+	}
+	return v, nil
 }
@@ -0,0 +1,174 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"golang.org/x/crypto/ssh"
+)
+
+var jumpHostAuthMethods = map[string]profiles.AuthMethod{
+	"SSH agent": profiles.AuthAgent,
+	"Key file":  profiles.AuthKeyFile,
+	"Password":  profiles.AuthPassword,
+}
+
+// single hop row of the jump hosts editor
+type jumpHostForm struct {
+	specEntry    *widget.Entry
+	authSelect   *widget.Select
+	keyPathEntry *widget.Entry
+	secretEntry  *widget.Entry
+	content      *fyne.Container
+}
+
+// jumpHostsEditor builds ProxyJump chain (bastions) for the connect dialog
+type jumpHostsEditor struct {
+	forms   []*jumpHostForm
+	list    *fyne.Container
+	content fyne.CanvasObject
+}
+
+func newJumpHostsEditor() *jumpHostsEditor {
+	e := &jumpHostsEditor{list: container.NewVBox()}
+	addButton := widget.NewButtonWithIcon("Add jump host", theme.ContentAddIcon(), func() {
+		e.addHop(profiles.JumpHost{AuthMethod: profiles.AuthAgent})
+	})
+	e.content = container.NewVBox(e.list, addButton)
+	return e
+}
+
+func (e *jumpHostsEditor) addHop(j profiles.JumpHost) {
+	f := &jumpHostForm{
+		specEntry:    widget.NewEntry(),
+		keyPathEntry: widget.NewEntry(),
+		secretEntry:  widget.NewPasswordEntry(),
+	}
+	f.specEntry.SetPlaceHolder("user@bastion:22")
+	f.specEntry.SetText(j.Spec)
+	f.keyPathEntry.SetPlaceHolder("path to private key")
+	f.keyPathEntry.SetText(j.KeyPath)
+
+	f.authSelect = widget.NewSelect([]string{"SSH agent", "Key file", "Password"}, func(name string) {
+		switch jumpHostAuthMethods[name] {
+		case profiles.AuthKeyFile:
+			f.keyPathEntry.Show()
+			f.secretEntry.Show()
+			f.secretEntry.SetPlaceHolder("passphrase (optional)")
+		case profiles.AuthPassword:
+			f.keyPathEntry.Hide()
+			f.secretEntry.Show()
+			f.secretEntry.SetPlaceHolder("password")
+		default:
+			f.keyPathEntry.Hide()
+			f.secretEntry.Hide()
+		}
+	})
+	for name, m := range jumpHostAuthMethods {
+		if m == j.AuthMethod {
+			f.authSelect.SetSelected(name)
+		}
+	}
+
+	removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.removeHop(f)
+	})
+	f.content = container.NewVBox(
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, container.NewHBox(f.authSelect, removeButton), f.specEntry),
+		f.keyPathEntry,
+		f.secretEntry,
+	)
+	e.forms = append(e.forms, f)
+	e.list.Add(f.content)
+}
+
+func (e *jumpHostsEditor) removeHop(f *jumpHostForm) {
+	for i := range e.forms {
+		if e.forms[i] == f {
+			e.forms = append(e.forms[:i], e.forms[i+1:]...)
+			break
+		}
+	}
+	e.list.Remove(f.content)
+}
+
+// replaces hops with profile ones
+func (e *jumpHostsEditor) Load(hops []profiles.JumpHost) {
+	e.forms = nil
+	e.list.RemoveAll()
+	for _, j := range hops {
+		e.addHop(j)
+	}
+}
+
+// returns hops for saving in profile, secrets are not included
+func (e *jumpHostsEditor) Profiles() []profiles.JumpHost {
+	out := make([]profiles.JumpHost, 0, len(e.forms))
+	for _, f := range e.forms {
+		j := profiles.JumpHost{
+			Spec:       strings.TrimSpace(f.specEntry.Text),
+			AuthMethod: jumpHostAuthMethods[f.authSelect.Selected],
+		}
+		if j.AuthMethod == profiles.AuthKeyFile {
+			j.KeyPath = strings.TrimSpace(f.keyPathEntry.Text)
+		}
+		out = append(out, j)
+	}
+	return out
+}
+
+// returns hops ready for dialing, closers have to be closed after connection is established
+func (e *jumpHostsEditor) Build() ([]gssh.JumpHost, []io.Closer, error) {
+	var closers []io.Closer
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+
+	out := make([]gssh.JumpHost, 0, len(e.forms))
+	for _, f := range e.forms {
+		user, address, err := gssh.ParseJumpHostSpec(f.specEntry.Text)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+
+		var auth ssh.AuthMethod
+		switch jumpHostAuthMethods[f.authSelect.Selected] {
+		case profiles.AuthKeyFile:
+			var key []byte
+			key, err = os.ReadFile(strings.TrimSpace(f.keyPathEntry.Text))
+			if err == nil {
+				auth, err = gssh.NewPrivKeyAuth(key, []byte(f.secretEntry.Text))
+			}
+		case profiles.AuthPassword:
+			auth = ssh.Password(f.secretEntry.Text)
+		default:
+			var closer io.Closer
+			auth, closer, err = gssh.NewAgentAuth()
+			if closer != nil {
+				closers = append(closers, closer)
+			}
+		}
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("jump host <%v>: %w", address, err)
+		}
+		out = append(out, gssh.JumpHost{Address: address, User: user, Auth: []ssh.AuthMethod{auth}})
+	}
+	return out, closers, nil
+}
+
+func (e *jumpHostsEditor) CanvasObject() fyne.CanvasObject {
+	return e.content
+}
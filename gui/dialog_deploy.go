@@ -1,11 +1,13 @@
 package gui
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
-	"time"
+	"strings"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -13,8 +15,10 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
+	"github.com/KiraCore/kensho/helper/deployplan"
 	"github.com/KiraCore/kensho/helper/gssh"
 	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
 	"github.com/KiraCore/kensho/types"
 )
 
@@ -58,12 +62,24 @@ func showDeployDialog(g *Gui, doneListener binding.DataListener, shidaiInfra bin
 			sudoPasswordEntryButton.Show()
 		}
 		log.Println("Sudo password is nil, assuming we connected with key")
-		} else if g.Host.UserPassword != nil && g.sshClient.User() != "root" {
-			sudoCheck.Set(true)
-			if !sudoPasswordEntryButton.Hidden {
+		go func() {
+			noPassword, err := gssh.SudoNoPasswordRequired(context.Background(), g.sshClient)
+			if err != nil {
+				log.Printf("Unable to check NOPASSWD sudo: %v", err)
+				return
+			}
+			if noPassword {
+				log.Println("sudo does not require password (NOPASSWD)")
+				sudoCheck.Set(true)
 				sudoPasswordEntryButton.Hide()
-				}
-			log.Println("Sudo password is not nil, applying password from connect dialog")
+			}
+		}()
+	} else if g.Host.UserPassword != nil && g.sshClient.User() != "root" {
+		sudoCheck.Set(true)
+		if !sudoPasswordEntryButton.Hidden {
+			sudoPasswordEntryButton.Hide()
+		}
+		log.Println("Sudo password is not nil, applying password from connect dialog")
 		sudoPasswordBinding.Set(*g.Host.UserPassword)
 	}
 
@@ -145,9 +161,6 @@ func showDeployDialog(g *Gui, doneListener binding.DataListener, shidaiInfra bin
 		return payload, nil
 	}
 
-	deployErrorBinding := binding.NewBool()
-	errorMessageBinding := binding.NewString()
-
 	deployButton := widget.NewButton("Deploy", func() {
 		payload, err := constructJoinCmd()
 		if err != nil {
@@ -157,81 +170,23 @@ func showDeployDialog(g *Gui, doneListener binding.DataListener, shidaiInfra bin
 
 		sP, _ := sudoPasswordBinding.Get()
 		sInfra, _ := shidaiInfra.Get()
-		if !sInfra {
-			sekaiVersion, interxVersion, err := httph.GetBinariesVersionsFromTrustedNode(payload.Args.IP, strconv.Itoa(payload.Args.RPCPort), strconv.Itoa(payload.Args.InterxPort))
-			if err != nil {
-				g.showErrorDialog(err, binding.NewDataListener(func() {}))
-				return
-			}
-
-			bootstrapFileUrl := types.BOOTSTRAP_SCRIPT
-			// filePathToSaveOnRemote := filepath.Join("/home/", g.sshClient.User(), "bootstrap.sh")
-			// var cmdForDeploy string:= fmt.Sprintf(`echo '%v' | sudo -S sh -c "%v --sekai=%v --interx=%v 2>&1"`, sP, filePathToSaveOnRemote, sekaiVersion, interxVersion)
-			var filePathToSaveOnRemote string
-			bootstrapFileName := "bootstrap.sh"
-
-			var cmdForDeploy string
-			if g.sshClient.User() == "root" {
-				filePathToSaveOnRemote = fmt.Sprintf("/%v/%v", g.sshClient.User(), bootstrapFileName)
-				cmdForDeploy = fmt.Sprintf(`%v --sekai=%v --interx=%v 2>&1`, filePathToSaveOnRemote, sekaiVersion, interxVersion)
-			} else {
-				filePathToSaveOnRemote = fmt.Sprintf("/home/%v/%v", g.sshClient.User(), bootstrapFileName)
-				cmdForDeploy = fmt.Sprintf(`echo '%v' | sudo -S sh -c "%v --sekai=%v --interx=%v 2>&1"`, sP, filePathToSaveOnRemote, sekaiVersion, interxVersion)
-			}
-			log.Println("Bootstrap file save path:", filePathToSaveOnRemote)
-			f, err := httph.MakeHttpRequest(bootstrapFileUrl, "GET")
-			if err != nil {
-				log.Println(err.Error())
-				g.showErrorDialog(fmt.Errorf("error when downloading bootstrap script: %v ", err.Error()), binding.NewDataListener(func() {}))
-				return
-			}
-			err = gssh.SendFileSFTP(g.sshClient, f, filePathToSaveOnRemote)
-			if err != nil {
-				log.Println(err.Error())
-				g.showErrorDialog(fmt.Errorf("error when sending bootstrap script to remove host: %v ", err.Error()), binding.NewDataListener(func() {}))
-				return
-			}
-
-			cmdForChmod := fmt.Sprintf(`chmod +x %v 2>&1`, filePathToSaveOnRemote)
-			showCmdExecDialogAndRunCmdV4(g, "Deploying", cmdForChmod, true, deployErrorBinding, errorMessageBinding)
-			errB, _ := deployErrorBinding.Get()
-			if errB {
-				errMsg, _ := errorMessageBinding.Get()
-				g.showErrorDialog(fmt.Errorf("error while checking the sudo password: %v ", errMsg), binding.NewDataListener(func() {}))
-				return
-			}
-
-			log.Printf("CMD for deploy: <%v>", cmdForDeploy)
-			showCmdExecDialogAndRunCmdV4(g, "Deploying", cmdForDeploy, true, deployErrorBinding, errorMessageBinding)
-			errB, _ = deployErrorBinding.Get()
-			if errB {
-				errMsg, _ := errorMessageBinding.Get()
-				g.showErrorDialog(fmt.Errorf("error while checking the sudo password: %v ", errMsg), binding.NewDataListener(func() {}))
-				return
-			}
-			time.Sleep(time.Second * 3)
-		}
-
-		g.WaitDialog.ShowWaitDialog()
-		jsonPayload, err := json.Marshal(payload)
-		if err != nil {
-			log.Printf("Failed to marshal JSON payload: %v", err)
-			return
-		}
-
-		log.Printf("Executing http payload for join: %+v", payload)
-		out, err := httph.ExecHttpRequestBySSHTunnel(g.sshClient, types.SEKIN_EXECUTE_ENDPOINT, "POST", jsonPayload)
-		log.Printf("ERROR:\n %v\nerr: %v", string(out), err)
-		g.WaitDialog.HideWaitDialog()
-
-		if err != nil {
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-			return
-		}
-
-		doneListener.DataChanged()
-		wizard.Hide()
-
+		// sekin is bootstrapped only when shidai is not running yet
+		plan, vars := deployplan.NewJoinPlan(deployplan.JoinParams{
+			TrustedIP:  payload.Args.IP,
+			RPCPort:    payload.Args.RPCPort,
+			InterxPort: payload.Args.InterxPort,
+			P2PPort:    payload.Args.P2PPort,
+			Local:      payload.Args.Local,
+			Mnemonic:   payload.Args.Mnemonic,
+			User:       g.sshClient.User(),
+			Bootstrap:  !sInfra,
+		})
+		env := &deployplan.Env{SudoPassword: sP}
+		wizard.Push("Deployment plan", makeDeployPlanView(g, wizard, g.Fleet.Selected(), deployplan.NewExecution(plan, vars, env), func() {
+			doneListener.DataChanged()
+			wizard.Hide()
+		}))
+		wizard.Resize(fyne.NewSize(700, 600))
 	})
 
 	deployButton.Disable()
@@ -299,18 +254,18 @@ func showSudoEnteringDialog(g *Gui, bindString binding.String, bindCheck binding
 	sudoPasswordEntry := widget.NewEntryWithData(bindString)
 	errorMessageBinding := binding.NewString()
 	checkSudoPassword := func(p string) error {
-		cmd := fmt.Sprintf("echo '%v' | sudo -S uname", p)
 		errB := binding.NewBool()
-		showCmdExecDialogAndRunCmdV4(g, "checking sudo password", cmd, true, errB, errorMessageBinding)
-		errExec, _ := errB.Get()
-		if errExec {
-			errMsg, err := errorMessageBinding.Get()
-			if err != nil {
-				return err
-			}
-			return fmt.Errorf("error while checking the sudo password: %v ", errMsg)
+		result := showSudoCmdExecDialog(g, "checking sudo password", "uname", p, true, errB, errorMessageBinding)
+		switch {
+		case result.ExitCode == 0:
+			return nil
+		case errors.Is(result.Err, gssh.ErrSudoPasswordRejected):
+			return fmt.Errorf("sudo password was rejected")
+		case result.ExitCode > 0:
+			return fmt.Errorf("sudo failed: %v", describeCommandFailure(result))
+		default:
+			return fmt.Errorf("error while checking the sudo password: %v", describeCommandFailure(result))
 		}
-		return nil
 	}
 
 	okButton := widget.NewButton("Ok", func() {
@@ -337,3 +292,143 @@ func showSudoEnteringDialog(g *Gui, bindString binding.String, bindCheck binding
 	wizard.Show(g.Window)
 
 }
+
+// returns exit status and stderr of failed command for error dialogs
+func describeCommandFailure(r gssh.ResultV2) string {
+	status := fmt.Sprintf("exit code %v", r.ExitCode)
+	if r.Signal != "" {
+		status = fmt.Sprintf("killed by SIG%v", r.Signal)
+	} else if r.ExitCode < 0 && r.Err != nil {
+		status = r.Err.Error()
+	}
+	stderr := strings.TrimSpace(r.Stderr)
+	if stderr == "" {
+		// commands with 2>&1 have their errors in stdout
+		stderr = strings.TrimSpace(r.Stdout)
+	}
+	if len(stderr) > 1000 {
+		stderr = "..." + stderr[len(stderr)-1000:]
+	}
+	if stderr == "" {
+		return status
+	}
+	return fmt.Sprintf("%v\n%v", status, stderr)
+}
+
+// shows steps of the plan with their state, dry-run preview and output of remote commands.
+// Failed plan can be resumed from the failed step, onDone is called once all steps are done.
+// Ssh client of conn is resolved on every run, so resume after reconnect uses the new one
+func makeDeployPlanView(g *Gui, wizard *dialogWizard.Wizard, conn *HostConnection, e *deployplan.Execution, onDone func()) fyne.CanvasObject {
+	icons := make([]*widget.Icon, len(e.Plan.Steps))
+	states := make([]*widget.Label, len(e.Plan.Steps))
+	steps := container.NewVBox()
+	for i, s := range e.Plan.Steps {
+		icons[i] = widget.NewIcon(theme.RadioButtonIcon())
+		states[i] = widget.NewLabel(string(deployplan.Pending))
+		steps.Add(container.NewBorder(nil, nil, icons[i], states[i], widget.NewLabel(fmt.Sprintf("%v. %v", i+1, s.Name))))
+	}
+
+	output := widget.NewLabel("")
+	output.Wrapping = fyne.TextWrapWord
+	outputScroll := container.NewVScroll(output)
+	e.Env.Output = &planOutput{label: output, scroll: outputScroll, redact: e.Vars.Redact}
+
+	e.OnProgress = func(p deployplan.Progress) {
+		states[p.Step].SetText(string(p.State))
+		switch p.State {
+		case deployplan.Running:
+			icons[p.Step].SetResource(theme.MediaPlayIcon())
+		case deployplan.Done:
+			icons[p.Step].SetResource(theme.ConfirmIcon())
+		case deployplan.Failed, deployplan.RolledBack:
+			icons[p.Step].SetResource(theme.ErrorIcon())
+		}
+	}
+
+	dryRunButton := widget.NewButtonWithIcon("Dry run", theme.SearchIcon(), func() {
+		preview := widget.NewLabel(deployplan.FormatDryRun(e.Plan.Name, e.Plan.DryRun(e.Vars)))
+		preview.TextStyle = fyne.TextStyle{Monospace: true}
+		wizard.Push("Dry run", container.NewScroll(preview))
+	})
+
+	var runButton, cancelButton *widget.Button
+	var cancel context.CancelFunc
+	runButton = widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		runButton.Disable()
+		dryRunButton.Disable()
+		cancelButton.Enable()
+		go func() {
+			defer cancel()
+			err := useCurrentClient(g, conn, e.Env)
+			if err == nil {
+				err = e.Run(ctx)
+			}
+			cancelButton.Disable()
+			dryRunButton.Enable()
+			if err != nil {
+				runButton.SetText("Resume")
+				runButton.Enable()
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				return
+			}
+			onDone()
+		}()
+	})
+	runButton.Importance = widget.HighImportance
+	cancelButton = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		cancelButton.Disable()
+		cancel()
+	})
+	cancelButton.Importance = widget.DangerImportance
+	cancelButton.Disable()
+
+	return container.NewBorder(
+		steps,
+		container.NewGridWithColumns(3, dryRunButton, runButton, cancelButton),
+		nil,
+		nil,
+		outputScroll,
+	)
+}
+
+// sets ssh client and shidai api of env to the current client of conn
+func useCurrentClient(g *Gui, conn *HostConnection, env *deployplan.Env) error {
+	client, err := g.clientProvider(conn)()
+	if err != nil {
+		return fmt.Errorf("unable to run deployment plan: %w", err)
+	}
+	env.Client = client
+	env.Shidai = shidaiclient.New(client, conn.Host.ShidaiPort)
+	return nil
+}
+
+// only the tail of the output is shown, so setting the text stays cheap for long running commands
+const planOutputLimit = 32 * 1024
+
+// appends output of remote commands to the plan view
+type planOutput struct {
+	mu     sync.Mutex
+	text   string
+	label  *widget.Label
+	scroll *container.Scroll
+	redact func(string) string
+}
+
+func (o *planOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	o.text += o.redact(cleanString(string(p)))
+	if len(o.text) > planOutputLimit {
+		// cut at line start when possible, so the first shown line is not partial
+		cut := len(o.text) - planOutputLimit
+		if i := strings.IndexByte(o.text[cut:], '\n'); i >= 0 {
+			cut += i + 1
+		}
+		o.text = o.text[cut:]
+	}
+	o.label.SetText(o.text)
+	o.mu.Unlock()
+	o.scroll.ScrollToBottom()
+	return len(p), nil
+}
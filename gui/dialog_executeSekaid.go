@@ -1,7 +1,7 @@
 package gui
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -11,8 +11,6 @@ import (
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/widget"
 	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
-	"github.com/KiraCore/kensho/helper/httph"
-	"github.com/KiraCore/kensho/types"
 )
 
 func showSekaiExecuteDialog(g *Gui) {
@@ -29,33 +27,22 @@ func showSekaiExecuteDialog(g *Gui) {
 		log.Printf("Trying to execute: %v", cmdEntry.Text)
 		cmd, _ := cmdData.Get()
 		cmd = strings.ReplaceAll(cmd, "\n", " ")
-		cmd = fmt.Sprintf("%v %v", sekaidCmdName, cmd)
 
 		cmdArgs := strings.Split(cmd, " ")
 		cmdArgs = RemoveEmptyAndWhitespaceStrings(cmdArgs)
 
-		cmdStruct := types.ExecSekaiCommands{Command: "sekaid", ExecArgs: types.ExecArgs{Exec: cmdArgs}}
-
-		payload, err := json.Marshal(cmdStruct)
-		if err != nil {
-			log.Printf("error when marshaling cmdStruct: %v", err.Error())
-			g.WaitDialog.HideWaitDialog()
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-			return
-		}
-
-		o, err := httph.ExecHttpRequestBySSHTunnel(g.sshClient, types.SEKIN_EXECUTE_ENDPOINT, "POST", payload)
+		o, err := g.shidai().Sekaid(context.Background(), cmdArgs)
 		if err != nil {
-			log.Printf("error when executing cmdStruct: %v", err.Error())
+			log.Printf("error when executing sekaid %v: %v", cmdArgs, err.Error())
 			g.WaitDialog.HideWaitDialog()
 			g.showErrorDialog(err, binding.NewDataListener(func() {}))
 			return
 		}
 
-		log.Printf("output of <%v>:\n%v", cmd, string(o))
+		log.Printf("output of <%v>:\n%v", cmd, o.Output)
 		g.WaitDialog.HideWaitDialog()
 
-		showInfoDialog(g, "Out", string(o))
+		showInfoDialog(g, "Out", o.Output)
 	})
 
 	submitButton := widget.NewButton("Submit", func() {
@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
+	"github.com/KiraCore/kensho/helper/gssh"
+)
+
+// single line input dialog, confirmAction is called with non empty trimmed value
+func showFileEntryDialog(g *Gui, title, label, value string, confirmAction func(value string)) {
+	var wizard *dialogWizard.Wizard
+
+	entry := widget.NewEntry()
+	entry.SetText(value)
+
+	confirmFunc := func() {
+		trimmed := strings.TrimSpace(entry.Text)
+		if trimmed == "" {
+			entry.SetValidationError(fmt.Errorf("value cannot be empty"))
+			return
+		}
+		wizard.Hide()
+		confirmAction(trimmed)
+	}
+	entry.OnSubmitted = func(s string) { confirmFunc() }
+
+	okButton := widget.NewButton("Ok", confirmFunc)
+	okButton.Importance = widget.HighImportance
+	cancelButton := widget.NewButton("Cancel", func() { wizard.Hide() })
+
+	content := container.NewVBox(
+		widget.NewLabel(label),
+		entry,
+		container.NewGridWithColumns(2, okButton, cancelButton),
+	)
+	wizard = dialogWizard.NewWizard(title, content)
+	wizard.Show(g.Window)
+	wizard.Resize(fyne.NewSize(400, 180))
+}
+
+// number of files transferred at the same time
+const parallelTransfers = 3
+
+// runs verified, resumable transfers and shows progress of every file, blocks until all transfers are finished
+func showTransfersDialog(g *Gui, title string, transfers []gssh.Transfer) []error {
+	var wizard *dialogWizard.Wizard
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bars := make([]*widget.ProgressBar, len(transfers))
+	statuses := make([]binding.String, len(transfers))
+	rows := container.NewVBox()
+	for i, t := range transfers {
+		bars[i] = widget.NewProgressBar()
+		statuses[i] = binding.NewString()
+		statuses[i].Set("Waiting...")
+		name := widget.NewLabel(t.String())
+		name.Truncation = fyne.TextTruncateEllipsis
+		rows.Add(container.NewVBox(name, widget.NewLabelWithData(statuses[i]), bars[i]))
+	}
+
+	summaryLabel := widget.NewLabel(fmt.Sprintf("Transferring %v file(s)", len(transfers)))
+	closeButton := widget.NewButton("Close", func() { wizard.Hide() })
+	closeButton.Hide()
+	var cancelButton *widget.Button
+	cancelButton = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		cancelButton.Disable()
+		summaryLabel.SetText("Cancelling, partial files are kept for resuming...")
+		cancel()
+	})
+	cancelButton.Importance = widget.DangerImportance
+
+	content := container.NewBorder(summaryLabel, container.NewVBox(cancelButton, closeButton), nil, nil, container.NewVScroll(rows))
+	wizard = dialogWizard.NewWizard(title, content)
+	wizard.Show(g.Window)
+	wizard.Resize(fyne.NewSize(500, 400))
+
+	lastUpdate := make([]time.Time, len(transfers))
+	errs := gssh.TransferAll(ctx, g.clientProvider(g.Fleet.Selected()), transfers, parallelTransfers, func(i int, done, total int64) {
+		// called for every chunk, widgets are refreshed only few times per second
+		if time.Since(lastUpdate[i]) < 200*time.Millisecond && done != total {
+			return
+		}
+		lastUpdate[i] = time.Now()
+		if total > 0 {
+			bars[i].SetValue(float64(done) / float64(total))
+		}
+		statuses[i].Set(fmt.Sprintf("%v / %v", formatFileSize(done), formatFileSize(total)))
+	})
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			statuses[i].Set(fmt.Sprintf("Failed: %v", err))
+		} else {
+			bars[i].SetValue(1)
+			statuses[i].Set("Done, sha256 verified")
+		}
+	}
+	summaryLabel.SetText(fmt.Sprintf("Finished: %v succeeded, %v failed", len(transfers)-failed, failed))
+	cancelButton.Hide()
+	closeButton.Show()
+	return errs
+}
+
+// returns size in human readable units (KiB, MiB...)
+func formatFileSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
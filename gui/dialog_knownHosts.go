@@ -0,0 +1,110 @@
+package gui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"golang.org/x/crypto/ssh"
+)
+
+// trust on first use prompt, blocks until user decides, so it must not be called from the ui event goroutine
+func (g *Gui) confirmHostKey(hostname string, key ssh.PublicKey) bool {
+	var wizard *dialogWizard.Wizard
+	decision := make(chan bool, 1)
+
+	infoLabel := widget.NewLabel(fmt.Sprintf(
+		"The authenticity of host <%v> can't be established.\n\n%v key fingerprint is:\n%v\n\nVerify the fingerprint with your host provider before trusting it. The key will be pinned and checked on every next connection.",
+		hostname, key.Type(), gssh.Fingerprint(key),
+	))
+	infoLabel.Wrapping = fyne.TextWrapWord
+
+	trustButton := widget.NewButtonWithIcon("Trust", theme.ConfirmIcon(), func() {
+		wizard.Hide()
+		decision <- true
+	})
+	trustButton.Importance = widget.HighImportance
+	rejectButton := widget.NewButtonWithIcon("Reject", theme.CancelIcon(), func() {
+		wizard.Hide()
+		decision <- false
+	})
+
+	content := container.NewBorder(
+		nil,
+		container.NewGridWithColumns(2, trustButton, rejectButton),
+		nil, nil,
+		container.NewVScroll(infoLabel),
+	)
+
+	g.WaitDialog.HideWaitDialog()
+	wizard = dialogWizard.NewWizard("Unknown host", content)
+	wizard.Show(g.Window)
+	wizard.Resize(fyne.NewSize(500, 350))
+
+	trusted := <-decision
+	log.Printf("Host key for <%v> trusted: %v", hostname, trusted)
+	if trusted {
+		g.WaitDialog.ShowWaitDialog()
+	}
+	return trusted
+}
+
+func showKnownHostsDialog(g *Gui) {
+	var wizard *dialogWizard.Wizard
+	var entries []gssh.KnownHost
+	var list *widget.List
+
+	reload := func() {
+		var err error
+		entries, err = g.KnownHosts.List()
+		if err != nil {
+			g.showErrorDialog(fmt.Errorf("unable to read known hosts: %w", err), binding.NewDataListener(func() {}))
+			return
+		}
+		list.Refresh()
+	}
+
+	list = widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("Template Object")
+			label.Wrapping = fyne.TextWrapBreak
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("Revoke", theme.DeleteIcon(), func() {}), label)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := entries[id]
+			c := item.(*fyne.Container)
+			c.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%v\n%v %v", strings.Join(entry.Hosts, ", "), entry.KeyType, entry.Fingerprint))
+			c.Objects[1].(*widget.Button).OnTapped = func() {
+				warningMessage := fmt.Sprintf("Revoke pinned key for <%v>?\n\n%v %v\n\nYou will be asked to verify the host key again on the next connection.", strings.Join(entry.Hosts, ", "), entry.KeyType, entry.Fingerprint)
+				showWarningMessageWithConfirmation(g, warningMessage, binding.NewDataListener(func() {
+					err := g.KnownHosts.Remove(entry)
+					if err != nil {
+						g.showErrorDialog(fmt.Errorf("unable to revoke host key: %w", err), binding.NewDataListener(func() {}))
+						return
+					}
+					reload()
+				}))
+			}
+		},
+	)
+
+	pathLabel := widget.NewLabel(g.KnownHosts.Path())
+	pathLabel.Wrapping = fyne.TextWrapBreak
+	closeButton := widget.NewButton("Close", func() { wizard.Hide() })
+
+	content := container.NewBorder(pathLabel, closeButton, nil, nil, list)
+	wizard = dialogWizard.NewWizard("Known hosts", content)
+	wizard.Show(g.Window)
+	wizard.Resize(fyne.NewSize(600, 400))
+	reload()
+}
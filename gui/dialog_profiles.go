@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
+)
+
+func showProfileNameDialog(g *Gui, nameBinding binding.String, confirmAction binding.DataListener) {
+	var wizard *dialogWizard.Wizard
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("validator-1")
+	name, _ := nameBinding.Get()
+	nameEntry.SetText(name)
+
+	infoLabel := widget.NewLabel("Passwords and passphrases are never saved, you will be asked for them on connect")
+	infoLabel.Wrapping = fyne.TextWrapWord
+
+	saveFunc := func() {
+		trimmed := strings.TrimSpace(nameEntry.Text)
+		if trimmed == "" {
+			nameEntry.SetValidationError(fmt.Errorf("profile name cannot be empty"))
+			return
+		}
+		nameBinding.Set(trimmed)
+		wizard.Hide()
+		confirmAction.DataChanged()
+	}
+	nameEntry.OnSubmitted = func(s string) { saveFunc() }
+
+	saveButton := widget.NewButton("Save", saveFunc)
+	saveButton.Importance = widget.HighImportance
+	cancelButton := widget.NewButton("Cancel", func() { wizard.Hide() })
+
+	content := container.NewVBox(
+		widget.NewLabel("Profile name"),
+		nameEntry,
+		infoLabel,
+		container.NewGridWithColumns(2, saveButton, cancelButton),
+	)
+
+	wizard = dialogWizard.NewWizard("Save connection profile", content)
+	wizard.Show(g.Window)
+	wizard.Resize(fyne.NewSize(350, 200))
+}
+
+// returns sorted names of saved profiles
+func getProfileNames(g *Gui) ([]string, error) {
+	list, err := g.Profiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list))
+	for _, p := range list {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
@@ -1,11 +1,14 @@
 package gui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -91,11 +94,33 @@ func showInfoDialog(g *Gui, infoTitle, infoString string) {
 	wizard.Resize(fyne.NewSize(400, 400))
 }
 
-func showCmdExecDialogAndRunCmdV4(g *Gui, infoMSG string, cmd string, autoHideCheck bool, errorBinding binding.Bool, errorMessageBinding binding.String) {
-	outputChannel := make(chan string)
+// runs command showing its output, returned result carries exit code and separated stdout/stderr for decisions
+func showCmdExecDialogAndRunCmdV4(g *Gui, infoMSG string, cmd string, autoHideCheck bool, errorBinding binding.Bool, errorMessageBinding binding.String) gssh.ResultV2 {
+	return showCmdExecDialog(g, infoMSG, cmd, autoHideCheck, errorBinding, errorMessageBinding,
+		func(ctx context.Context, outputChannel chan<- gssh.OutputLine, resultChannel chan<- gssh.ResultV2) {
+			gssh.ExecuteSSHCommandV4(ctx, g.sshClient, cmd, outputChannel, resultChannel)
+		})
+}
+
+// same as showCmdExecDialogAndRunCmdV4 but cmd is run with sudo, password is passed through stdin.
+// root user runs cmd directly
+func showSudoCmdExecDialog(g *Gui, infoMSG string, cmd string, sudoPassword string, autoHideCheck bool, errorBinding binding.Bool, errorMessageBinding binding.String) gssh.ResultV2 {
+	if g.sshClient.User() == "root" {
+		return showCmdExecDialogAndRunCmdV4(g, infoMSG, cmd, autoHideCheck, errorBinding, errorMessageBinding)
+	}
+	return showCmdExecDialog(g, infoMSG, cmd, autoHideCheck, errorBinding, errorMessageBinding,
+		func(ctx context.Context, outputChannel chan<- gssh.OutputLine, resultChannel chan<- gssh.ResultV2) {
+			gssh.ExecuteSSHSudoCommand(ctx, g.sshClient, cmd, sudoPassword, outputChannel, resultChannel)
+		})
+}
+
+func showCmdExecDialog(g *Gui, infoMSG string, cmd string, autoHideCheck bool, errorBinding binding.Bool, errorMessageBinding binding.String,
+	run func(ctx context.Context, outputChannel chan<- gssh.OutputLine, resultChannel chan<- gssh.ResultV2)) gssh.ResultV2 {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outputChannel := make(chan gssh.OutputLine)
 	errorChannel := make(chan gssh.ResultV2)
-	// go gssh.ExecuteSSHCommandV2(g.sshClient, cmd, outputChannel, errorChannel)
-	go gssh.ExecuteSSHCommandV3(g.sshClient, cmd, outputChannel, errorChannel)
+	go run(ctx, outputChannel, errorChannel)
 
 	var wizard *dialogWizard.Wizard
 	outputMsg := binding.NewString()
@@ -107,11 +132,18 @@ func showCmdExecDialogAndRunCmdV4(g *Gui, infoMSG string, cmd string, autoHideCh
 	label.Wrapping = fyne.TextWrapWord
 
 	closeButton := widget.NewButton("Done", func() { wizard.Hide() })
+	var cancelButton *widget.Button
+	cancelButton = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		cancelButton.Disable()
+		statusMsg.Set("Cancelling...")
+		cancel()
+	})
+	cancelButton.Importance = widget.DangerImportance
 	outputScroll := container.NewVScroll(label)
 
 	loadingDialog := container.NewBorder(
 		widget.NewLabelWithData(statusMsg),
-		container.NewVBox(loadingWidget, closeButton),
+		container.NewVBox(loadingWidget, cancelButton, closeButton),
 		nil,
 		nil,
 		container.NewHScroll(outputScroll),
@@ -129,7 +161,10 @@ func showCmdExecDialogAndRunCmdV4(g *Gui, infoMSG string, cmd string, autoHideCh
 	go func() {
 		defer wg.Done()
 		for line := range outputChannel {
-			cleanLine := cleanString(line)
+			cleanLine := cleanString(line.Text)
+			if line.Stream == gssh.Stderr {
+				cleanLine = "[stderr] " + cleanLine
+			}
 			out = fmt.Sprintf("%s\n%s", out, cleanLine)
 			outputMsg.Set(out)
 			outputScroll.ScrollToBottom()
@@ -145,18 +180,32 @@ func showCmdExecDialogAndRunCmdV4(g *Gui, infoMSG string, cmd string, autoHideCh
 	wg.Wait()
 
 	loadingWidget.Hide()
+	cancelButton.Hide()
 	closeButton.Show()
-	if errcheck.Err != nil {
+	duration := errcheck.Duration.Round(time.Second)
+	switch {
+	case errors.Is(errcheck.Err, gssh.ErrCommandCancelled):
+		log.Printf("Cancelled executing: <%v> after %v", cmd, duration)
+		errorBinding.Set(true)
+		errorMessageBinding.Set(fmt.Sprintf("Out: %v, Error: %v", string(out), errcheck.Err.Error()))
+		wizard.ChangeTitle("Cancelled")
+		statusMsg.Set(fmt.Sprintf("Cancelled after %v", duration))
+	case errcheck.Err != nil:
 		log.Printf("Unable to execute executing: <%v>, error: %v, %v ", cmd, errcheck.Err.Error(), string(out))
 		errorBinding.Set(true)
 		errorMessageBinding.Set(fmt.Sprintf("Out: %v, Error: %v", string(out), errcheck.Err.Error()))
-		statusMsg.Set(fmt.Sprintf("Error:\n%s", errcheck.Err))
-	} else {
+		status := fmt.Sprintf("exit code %v", errcheck.ExitCode)
+		if errcheck.Signal != "" {
+			status = fmt.Sprintf("killed by SIG%v", errcheck.Signal)
+		}
+		statusMsg.Set(fmt.Sprintf("Error (%v, %v):\n%s", status, duration, errcheck.Err))
+	default:
 		errorBinding.Set(false)
 		wizard.ChangeTitle("Done")
-		statusMsg.Set("Successes")
+		statusMsg.Set(fmt.Sprintf("Successes (%v)", duration))
 	}
 	outputScroll.ScrollToBottom()
+	return errcheck
 }
 
 func cleanString(s string) string {
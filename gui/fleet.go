@@ -0,0 +1,579 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"github.com/KiraCore/kensho/helper/alerting"
+	"github.com/KiraCore/kensho/helper/cometrpc"
+	commandcontroller "github.com/KiraCore/kensho/helper/commandController"
+	"github.com/KiraCore/kensho/helper/exporter"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/interxclient"
+	"github.com/KiraCore/kensho/helper/metrics"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/helper/signmonitor"
+	"github.com/fyne-io/terminal"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	reconnectInitialDelay = 2 * time.Second
+	reconnectMaxDelay     = time.Minute
+
+	// number of recent blocks kept by signing monitor and miss rate which raises alert
+	signingWindowSize           = 300
+	defaultSigningMissThreshold = 0.05
+
+	// alert rules are evaluated and metrics sampled at this interval
+	hostCheckInterval = 30 * time.Second
+)
+
+// HostConnection is a single connected host of the fleet
+type HostConnection struct {
+	Name      string
+	Host      *Host
+	Terminal  Terminal
+	Forwards  *gssh.ForwardManager
+	Signing   *signmonitor.Monitor
+	sshClient *ssh.Client
+	// sekai rpc shared by monitors and screens of the connection, see hostRPC
+	rpc          *cometrpc.Client
+	rpcTransport *http.Transport
+	// stops background monitors of the connection
+	stopMonitors context.CancelFunc
+	connected    bool
+	// dial opens new ssh client with the same credentials, used for reconnecting
+	dial             func() (*ssh.Client, error)
+	reconnectAttempt int
+	keepAlive        gssh.KeepAliveConfig
+	latency          time.Duration
+}
+
+// Fleet holds all concurrently connected hosts, Gui operates on the selected one
+type Fleet struct {
+	mu          sync.Mutex
+	connections map[string]*HostConnection
+	selected    string
+
+	HostsBinding    binding.StringList
+	SelectedBinding binding.String
+	// ssh round trip time of the selected host
+	LatencyBinding binding.String
+}
+
+func NewFleet() *Fleet {
+	return &Fleet{
+		connections:     make(map[string]*HostConnection),
+		HostsBinding:    binding.NewStringList(),
+		SelectedBinding: binding.NewString(),
+		LatencyBinding:  binding.NewString(),
+	}
+}
+
+// adds connection to the fleet, returns previous connection with the same name if it existed
+func (f *Fleet) Add(c *HostConnection) *HostConnection {
+	f.mu.Lock()
+	old := f.connections[c.Name]
+	f.connections[c.Name] = c
+	f.mu.Unlock()
+	f.updateHostsBinding()
+	return old
+}
+
+func (f *Fleet) Remove(name string) *HostConnection {
+	f.mu.Lock()
+	c := f.connections[name]
+	delete(f.connections, name)
+	if f.selected == name {
+		f.selected = ""
+	}
+	f.mu.Unlock()
+	f.updateHostsBinding()
+	return c
+}
+
+func (f *Fleet) Get(name string) (*HostConnection, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.connections[name]
+	return c, ok
+}
+
+// checks if this exact connection is still part of the fleet (was not removed or replaced)
+func (f *Fleet) Contains(c *HostConnection) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections[c.Name] == c
+}
+
+// returns connections sorted by name
+func (f *Fleet) List() []*HostConnection {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]*HostConnection, 0, len(f.connections))
+	for _, c := range f.connections {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func (f *Fleet) Selected() *HostConnection {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections[f.selected]
+}
+
+func (f *Fleet) setSelected(name string) {
+	f.mu.Lock()
+	f.selected = name
+	f.mu.Unlock()
+	f.SelectedBinding.Set(name)
+}
+
+func (f *Fleet) setConnected(c *HostConnection, state bool) {
+	f.mu.Lock()
+	c.connected = state
+	f.mu.Unlock()
+}
+
+func (f *Fleet) IsConnected(c *HostConnection) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return c.connected
+}
+
+// returns current ssh client of the connection, it is replaced after every reconnect
+func (f *Fleet) Client(c *HostConnection) *ssh.Client {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return c.sshClient
+}
+
+// returns reconnect attempt number, 0 if connection is not reconnecting
+func (f *Fleet) ReconnectAttempt(c *HostConnection) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return c.reconnectAttempt
+}
+
+func (f *Fleet) setReconnectAttempt(c *HostConnection, attempt int) {
+	f.mu.Lock()
+	c.reconnectAttempt = attempt
+	f.mu.Unlock()
+}
+
+// returns last keepalive round trip time, 0 if unknown
+func (f *Fleet) Latency(c *HostConnection) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return c.latency
+}
+
+func (f *Fleet) setLatency(c *HostConnection, rtt time.Duration) {
+	f.mu.Lock()
+	c.latency = rtt
+	selected := f.connections[f.selected] == c
+	f.mu.Unlock()
+	if selected {
+		f.updateLatencyBinding(c)
+	}
+}
+
+func (f *Fleet) updateLatencyBinding(c *HostConnection) {
+	f.mu.Lock()
+	connected, rtt := c.connected, c.latency
+	f.mu.Unlock()
+	switch {
+	case !connected:
+		f.LatencyBinding.Set("Latency: -")
+	case rtt == 0:
+		f.LatencyBinding.Set("Latency: measuring...")
+	default:
+		f.LatencyBinding.Set(fmt.Sprintf("Latency: %v", rtt.Round(time.Millisecond)))
+	}
+}
+
+func (f *Fleet) setClient(c *HostConnection, client *ssh.Client) {
+	f.mu.Lock()
+	c.sshClient = client
+	c.connected = true
+	c.reconnectAttempt = 0
+	c.latency = 0
+	f.mu.Unlock()
+}
+
+func (f *Fleet) updateHostsBinding() {
+	list := f.List()
+	names := make([]string, 0, len(list))
+	for _, c := range list {
+		names = append(names, c.Name)
+	}
+	f.HostsBinding.Set(names)
+}
+
+// adds freshly connected host to the fleet and switches gui to it
+func (g *Gui) addHostConnection(c *HostConnection) {
+	if c.Forwards == nil {
+		c.Forwards = gssh.NewForwardManager()
+	}
+	c.Forwards.SetClient(c.sshClient)
+	g.startMonitors(c)
+	g.Fleet.setConnected(c, true)
+	old := g.Fleet.Add(c)
+	if old != nil {
+		log.Printf("Replacing existing connection <%v>", old.Name)
+		closeHostConnection(old)
+	}
+	go g.superviseHostConnection(c)
+	g.selectHost(c.Name)
+}
+
+// switches gui to the host, every screen operates on the selected host
+func (g *Gui) selectHost(name string) {
+	c, ok := g.Fleet.Get(name)
+	if !ok {
+		return
+	}
+	if g.Fleet.Selected() == c && g.sshClient == c.sshClient {
+		return
+	}
+	log.Printf("Selecting host <%v>", name)
+	g.cancelTabRoutines(g.currentTabID)
+
+	g.Fleet.setSelected(name)
+	g.sshClient = c.sshClient
+	g.Host = c.Host
+	g.Terminal = c.Terminal
+	g.ConnectionStatusBinding.Set(g.Fleet.IsConnected(c))
+	g.Fleet.updateLatencyBinding(c)
+	g.connectionListener.DataChanged()
+	g.refreshCurrentTab()
+}
+
+// closes connection of the selected host and removes it from the fleet
+func (g *Gui) disconnectSelectedHost() {
+	c := g.Fleet.Selected()
+	if c == nil {
+		return
+	}
+	log.Printf("Disconnecting host <%v>", c.Name)
+	g.cancelTabRoutines(g.currentTabID)
+	g.Fleet.Remove(c.Name)
+	closeHostConnection(c)
+
+	list := g.Fleet.List()
+	if len(list) > 0 {
+		g.selectHost(list[0].Name)
+		return
+	}
+	g.ConnectionStatusBinding.Set(false)
+	g.Fleet.LatencyBinding.Set("")
+	g.ShowConnect()
+}
+
+// starts monitors which run in background for the whole life of connection, they survive reconnects
+func (g *Gui) startMonitors(c *HostConnection) {
+	var ctx context.Context
+	ctx, c.stopMonitors = context.WithCancel(context.Background())
+
+	c.rpc, c.rpcTransport = g.newHostRPC(c)
+	c.Signing = signmonitor.NewMonitor(c.rpc, signingWindowSize, defaultSigningMissThreshold)
+	c.Signing.OnAlert = func(a signmonitor.Alert) {
+		if a.Resolved {
+			g.Alerts.Resolve(c.Name, alerting.RuleSigningMissRate, a.String())
+			return
+		}
+		g.Alerts.Fire(c.Name, alerting.RuleSigningMissRate, alerting.Critical, a.String())
+	}
+	go c.Signing.Run(ctx)
+	go g.runHostChecks(ctx, c)
+}
+
+// evaluates alert rules and records metrics of connection until ctx is done, alerts of the host are dropped afterwards
+func (g *Gui) runHostChecks(ctx context.Context, c *HostConnection) {
+	defer g.Alerts.Forget(c.Name)
+	if g.Exporter != nil {
+		defer g.Exporter.Remove(c.Name)
+	}
+	ticker := time.NewTicker(hostCheckInterval)
+	defer ticker.Stop()
+	for {
+		snapshot := alerting.Snapshot{Host: c.Name, Connected: g.Fleet.IsConnected(c), Time: time.Now()}
+		if snapshot.Connected {
+			snapshot.Dashboard, snapshot.Err = shidaiclient.New(g.Fleet.Client(c), c.Host.ShidaiPort).Dashboard(ctx)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		g.Alerts.Evaluate(snapshot)
+		if snapshot.Connected {
+			g.recordMetrics(ctx, c, snapshot)
+		}
+		if g.Exporter != nil {
+			g.exportHost(ctx, c, snapshot)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stores sample of host metrics, values which cannot be fetched are left out of the sample
+func (g *Gui) recordMetrics(ctx context.Context, c *HostConnection, snapshot alerting.Snapshot) {
+	sample := metrics.NewSample(snapshot.Time)
+	if rtt := g.Fleet.Latency(c); rtt > 0 {
+		sample.Values[metrics.SSHRTT] = float64(rtt.Microseconds()) / 1000
+	}
+	if d := snapshot.Dashboard; d != nil {
+		for metric, value := range map[metrics.Metric]string{
+			metrics.Streak:         d.Streak,
+			metrics.Mischance:      d.Mischance,
+			metrics.ProducedBlocks: d.ProducedBlocks,
+		} {
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				sample.Values[metric] = v
+			}
+		}
+	}
+	// rpc client of the connection is reused, so sampling every host does not open new tunnels on each tick
+	if status, err := c.rpc.Status(ctx); err == nil {
+		if v, err := strconv.ParseFloat(status.Result.SyncInfo.LatestBlockHeight, 64); err == nil {
+			sample.Values[metrics.BlockHeight] = v
+		}
+	}
+	client := g.Fleet.Client(c)
+	if netInfo, err := interxclient.New(fmt.Sprintf("http://localhost:%v", c.Host.InterxPort), httph.TunnelClient(client)).NetInfo(ctx); err == nil {
+		sample.Values[metrics.Peers] = float64(netInfo.NPeers)
+	}
+	if err := g.Metrics.Append(c.Name, sample); err != nil {
+		log.Printf("Unable to record metrics of <%v>: %v", c.Name, err)
+	}
+}
+
+// passes data of host to prometheus exporter, shidai and interx status are fetched only for the exporter
+func (g *Gui) exportHost(ctx context.Context, c *HostConnection, snapshot alerting.Snapshot) {
+	data := exporter.HostData{
+		Host:      c.Name,
+		Connected: snapshot.Connected,
+		SSHRTT:    g.Fleet.Latency(c),
+		Dashboard: snapshot.Dashboard,
+		Updated:   snapshot.Time,
+	}
+	if snapshot.Connected {
+		client := g.Fleet.Client(c)
+		if status, err := shidaiclient.New(client, c.Host.ShidaiPort).Status(ctx); err == nil {
+			data.ShidaiStatus = &status
+		}
+		if status, err := interxclient.New(fmt.Sprintf("http://localhost:%v", c.Host.InterxPort), httph.TunnelClient(client)).Status(ctx); err == nil {
+			data.InterxStatus = status
+		}
+	}
+	g.Exporter.Update(data)
+}
+
+// delivers alerts as desktop notifications
+func desktopNotifier() alerting.Notifier {
+	return alerting.NotifierFunc(func(_ context.Context, n alerting.Notification) error {
+		title := fmt.Sprintf("%v: %v alert", n.Host, n.Severity)
+		if n.Resolved {
+			title = fmt.Sprintf("%v: alert resolved", n.Host)
+		}
+		fyne.CurrentApp().SendNotification(fyne.NewNotification(title, n.Message))
+		return nil
+	})
+}
+
+func closeHostConnection(c *HostConnection) {
+	if c.stopMonitors != nil {
+		c.stopMonitors()
+	}
+	if c.rpcTransport != nil {
+		c.rpcTransport.CloseIdleConnections()
+	}
+	if c.Forwards != nil {
+		c.Forwards.CloseAll()
+	}
+	if c.Terminal.SSHSessionForTerminal != nil {
+		c.Terminal.SSHSessionForTerminal.Close()
+	}
+	if c.sshClient != nil {
+		c.sshClient.Close()
+	}
+}
+
+// returns provider of the current client of connection, long running operations use it to continue after reconnect
+func (g *Gui) clientProvider(c *HostConnection) gssh.ClientProvider {
+	return func() (*ssh.Client, error) {
+		if c == nil || !g.Fleet.Contains(c) {
+			return nil, fmt.Errorf("host was disconnected")
+		}
+		if !g.Fleet.IsConnected(c) {
+			return nil, fmt.Errorf("<%v> is not connected, waiting for reconnect", c.Name)
+		}
+		return g.Fleet.Client(c), nil
+	}
+}
+
+// shidai api of the selected host, client is cheap to create since tunnel connections are pooled per ssh client
+func (g *Gui) shidai() shidaiclient.Client {
+	return shidaiclient.New(g.sshClient, g.Host.ShidaiPort)
+}
+
+// lifecycle controller of sekin components on the selected host, health is checked through ssh tunnel
+func (g *Gui) components() *commandcontroller.Controller {
+	client := g.sshClient
+	conn := g.Fleet.Selected()
+	sudoPassword := ""
+	if g.Host.UserPassword != nil {
+		sudoPassword = *g.Host.UserPassword
+	}
+	interx := interxclient.New(fmt.Sprintf("http://localhost:%v", g.Host.InterxPort), httph.TunnelClient(client))
+	return commandcontroller.New(commandcontroller.SSHRunner(client, sudoPassword), map[commandcontroller.Component]commandcontroller.HealthCheck{
+		commandcontroller.Sekai: func(ctx context.Context) error {
+			if conn == nil {
+				return fmt.Errorf("no host is selected")
+			}
+			_, err := conn.rpc.Status(ctx)
+			return err
+		},
+		commandcontroller.Interx: func(ctx context.Context) error {
+			_, err := interx.Status(ctx)
+			return err
+		},
+		commandcontroller.Shidai: func(ctx context.Context) error {
+			_, err := g.shidai().Status(ctx)
+			return err
+		},
+	})
+}
+
+// sekai rpc of connection which keeps working after reconnect, created once in startMonitors
+func (g *Gui) hostRPC(c *HostConnection) *cometrpc.Client {
+	return c.rpc
+}
+
+// every dial uses the current ssh client, idle connections of the transport are closed when ssh client goes away
+func (g *Gui) newHostRPC(c *HostConnection) (*cometrpc.Client, *http.Transport) {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		client, err := g.clientProvider(c)()
+		if err != nil {
+			return nil, err
+		}
+		return client.DialContext(ctx, network, addr)
+	}
+	transport := &http.Transport{
+		DialContext:         dial,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return cometrpc.New(fmt.Sprintf("http://localhost:%v", c.Host.RPCPort), &http.Client{Transport: transport}, dial), transport
+}
+
+// superviseHostConnection keeps connection alive with keepalives and reconnects it when link is lost,
+// until connection is removed from the fleet
+func (g *Gui) superviseHostConnection(c *HostConnection) {
+	g.ConnectionCount.Add(1)
+	for {
+		client := g.Fleet.Client(c)
+		go gssh.KeepAlive(client, c.keepAlive, func(rtt time.Duration) {
+			g.Fleet.setLatency(c, rtt)
+		})
+
+		err := client.Wait()
+		// pooled rpc connections were tunneled through the lost client
+		c.rpcTransport.CloseIdleConnections()
+		if !g.Fleet.Contains(c) {
+			log.Printf("SSH connection <%v> closed", c.Name)
+			return
+		}
+		g.Fleet.setConnected(c, false)
+		if g.Fleet.Selected() == c {
+			g.Fleet.updateLatencyBinding(c)
+		}
+		log.Printf("SSH connection <%v> was interrupted: %v", c.Name, err)
+		if g.Fleet.Selected() == c {
+			g.ConnectionStatusBinding.Set(false)
+		}
+
+		if c.dial == nil {
+			g.showErrorDialog(fmt.Errorf("SSH connection to <%v> was disconnected, reason: %v", c.Name, err), binding.NewDataListener(func() {}))
+			return
+		}
+		err = g.reconnectHostConnection(c)
+		if err != nil {
+			if g.Fleet.Contains(c) {
+				g.showErrorDialog(fmt.Errorf("unable to reconnect to <%v>, reason: %v", c.Name, err), binding.NewDataListener(func() {}))
+			}
+			return
+		}
+	}
+}
+
+// redials connection with exponential backoff and restores terminal, log streams and polling loops of the selected host.
+// Returns nil when connection is restored or removed from the fleet, error when reconnecting makes no sense (host key changed)
+func (g *Gui) reconnectHostConnection(c *HostConnection) error {
+	delay := reconnectInitialDelay
+	for attempt := 1; ; attempt++ {
+		g.Fleet.setReconnectAttempt(c, attempt)
+		log.Printf("Reconnecting to <%v> in %v (attempt %v)", c.Name, delay, attempt)
+		time.Sleep(delay)
+		if !g.Fleet.Contains(c) {
+			return nil
+		}
+
+		client, err := c.dial()
+		if err == nil {
+			if !g.Fleet.Contains(c) {
+				client.Close()
+				return nil
+			}
+			g.restoreHostConnection(c, client)
+			return nil
+		}
+
+		var mismatchErr *gssh.HostKeyMismatchError
+		if errors.Is(err, gssh.ErrHostKeyRejected) || errors.As(err, &mismatchErr) {
+			g.Fleet.setReconnectAttempt(c, 0)
+			return err
+		}
+		log.Printf("Reconnect to <%v> failed: %v", c.Name, err)
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+}
+
+// swaps ssh client of the connection and re-establishes terminal session,
+// current tab of the selected host is re-rendered so log streams and polling loops are started again
+func (g *Gui) restoreHostConnection(c *HostConnection, client *ssh.Client) {
+	log.Printf("SSH connection <%v> restored", c.Name)
+	if c.Terminal.SSHSessionForTerminal != nil {
+		c.Terminal.SSHSessionForTerminal.Close()
+	}
+	g.Fleet.setClient(c, client)
+	c.Forwards.SetClient(client)
+	err := TryToRunSSHSessionForTerminal(c)
+	if err != nil {
+		log.Printf("Unable to restore terminal of <%v>: %v", c.Name, err)
+		c.Terminal.Term = terminal.New()
+	}
+	if g.Fleet.Selected() == c {
+		g.selectHost(c.Name)
+	}
+}
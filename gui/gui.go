@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/alerting"
+	"github.com/KiraCore/kensho/helper/exporter"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/metrics"
+	"github.com/KiraCore/kensho/helper/profiles"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -20,17 +27,35 @@ type Gui struct {
 	Window                  fyne.Window
 	WaitDialog              *WaitDialog
 	HomeFolder              string
+	KnownHosts              *gssh.KnownHosts
+	Profiles                *profiles.Store
+	AlertConfig             *alerting.ConfigStore
+	Alerts                  *alerting.Engine
+	Metrics                 *metrics.Store
+	Exporter                *exporter.Exporter // nil unless prometheus metrics are served
 	Host                    *Host
+	Fleet                   *Fleet
 	ConnectionStatusBinding binding.Bool
-	ConnectionCount         int
+	ConnectionCount         atomic.Int64 // incremented by supervisor goroutine of every host
 	Terminal                Terminal
 	LogCtx                  context.Context
 	LogCtxCancel            context.CancelFunc
 	NodeInfo                nodeInfoScreen
+	FleetOverview           fleetOverviewScreen
+	Files                   filesScreen
+	Forwards                forwardsScreen
+	Signing                 signingScreen
+	AlertsView              alertsScreen
 	TxExec                  TxExecBinding
 
 	DeveloperMode bool
 	Version       string
+
+	connectionListener binding.DataListener
+	currentTabID       string
+	currentTab         Tab
+	navTree            *widget.Tree
+	setTab             func(t Tab)
 }
 
 type TxExecBinding struct {
@@ -40,10 +65,14 @@ type TxExecBinding struct {
 type Host struct {
 	IP           string
 	UserPassword *string
+	ShidaiPort   int
+	InterxPort   int
+	RPCPort      int
 }
 
 func (g *Gui) MakeGui() fyne.CanvasObject {
 	log.Printf("Developer mode: %v\n", g.DeveloperMode)
+	g.Alerts = alerting.NewEngine(g.AlertConfig, alerting.DefaultRules(), desktopNotifier())
 
 	title := widget.NewLabel(appName)
 	info := widget.NewLabel("Welcome to  Kensho. Navigate trough panel on the left side")
@@ -85,8 +114,9 @@ func (g *Gui) MakeGui() fyne.CanvasObject {
 
 	tab := container.NewBorder(container.NewVBox(title, info), container.NewVBox(txExecLoadingWidget, reconnectButton), nil, nil, mainWindow)
 
+	g.Fleet = NewFleet()
 	g.ConnectionStatusBinding = binding.NewBool()
-	g.ConnectionStatusBinding.AddListener(binding.NewDataListener(func() {
+	g.connectionListener = binding.NewDataListener(func() {
 		state, _ := g.ConnectionStatusBinding.Get()
 		if state {
 			g.Window.SetTitle(fmt.Sprintf("%v (connected) - %v", appName, g.Host.IP))
@@ -94,23 +124,25 @@ func (g *Gui) MakeGui() fyne.CanvasObject {
 			tab.Refresh()
 		} else {
 			g.Window.SetTitle(fmt.Sprintf("%v (not connected)", appName))
-			if g.ConnectionCount != 0 {
-				log.Println("reconnect button show triggered, connection count:", g.ConnectionCount)
+			if g.ConnectionCount.Load() != 0 {
+				log.Println("reconnect button show triggered, connection count:", g.ConnectionCount.Load())
 				reconnectButton.Show()
 			}
 		}
-	}))
+	})
+	g.ConnectionStatusBinding.AddListener(g.connectionListener)
 
-	setTab := func(t Tab) {
+	g.setTab = func(t Tab) {
 		title.SetText(t.Title)
 		info.SetText(t.Info)
 		mainWindow.Objects = []fyne.CanvasObject{t.View(g.Window, g)}
+		mainWindow.Refresh()
 	}
 	versionData := binding.NewString()
 	versionData.Set(fmt.Sprintf("Version: %v", g.Version))
 	versionLabel := widget.NewLabelWithData(versionData)
 	versionLabel.Alignment = fyne.TextAlignCenter
-	menuAndTab := container.NewHSplit(container.NewBorder(nil, versionLabel, nil, nil, g.makeNav(setTab)), tab)
+	menuAndTab := container.NewHSplit(container.NewBorder(g.makeHostSelector(), versionLabel, nil, nil, g.makeNav(g.setTab)), tab)
 	menuAndTab.Offset = 0.2
 	return menuAndTab
 
@@ -153,21 +185,102 @@ func (g *Gui) makeNav(setTab func(t Tab)) fyne.CanvasObject {
 				// }
 				// fmt.Println(uid)
 				a.Preferences().SetString(preferenceCurrent, uid)
+				g.currentTabID = uid
+				g.currentTab = t
 				setTab(t)
 			}
 		},
 		OnUnselected: func(uid widget.TreeNodeID) {
-			switch uid {
-			case "logs":
-				log.Println("Unselected: ", uid)
-				g.LogCtxCancel()
-			case "nodeInfo":
-				log.Println("Unselected: ", uid)
-				g.NodeInfo.ctxCancel()
-			}
-
+			g.cancelTabRoutines(uid)
 		},
 	}
 
+	g.navTree = tree
 	return tree
 }
+
+// host selector on top of navigation tree, switching host re-renders current tab for selected host
+func (g *Gui) makeHostSelector() fyne.CanvasObject {
+	hostSelect := widget.NewSelect([]string{}, func(name string) {
+		g.selectHost(name)
+	})
+	hostSelect.PlaceHolder = "No hosts connected"
+
+	g.Fleet.HostsBinding.AddListener(binding.NewDataListener(func() {
+		names, _ := g.Fleet.HostsBinding.Get()
+		hostSelect.Options = names
+		hostSelect.Refresh()
+	}))
+	g.Fleet.SelectedBinding.AddListener(binding.NewDataListener(func() {
+		name, _ := g.Fleet.SelectedBinding.Get()
+		if hostSelect.Selected != name {
+			hostSelect.SetSelected(name)
+		}
+	}))
+
+	addHostButton := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		g.ShowConnect()
+	})
+	disconnectHostButton := widget.NewButtonWithIcon("", theme.LogoutIcon(), func() {
+		c := g.Fleet.Selected()
+		if c == nil {
+			return
+		}
+		showWarningMessageWithConfirmation(g, fmt.Sprintf("Disconnect from <%v>?", c.Name), binding.NewDataListener(func() {
+			g.disconnectSelectedHost()
+		}))
+	})
+
+	latencyLabel := widget.NewLabelWithData(g.Fleet.LatencyBinding)
+	latencyLabel.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(addHostButton, disconnectHostButton), hostSelect),
+		latencyLabel,
+	)
+}
+
+// stops background goroutines of the tab (log streams, refresh loops)
+func (g *Gui) cancelTabRoutines(uid string) {
+	switch uid {
+	case "logs":
+		log.Println("Unselected: ", uid)
+		if g.LogCtxCancel != nil {
+			g.LogCtxCancel()
+		}
+	case "nodeInfo":
+		log.Println("Unselected: ", uid)
+		if g.NodeInfo.ctxCancel != nil {
+			g.NodeInfo.ctxCancel()
+		}
+	case "fleet":
+		log.Println("Unselected: ", uid)
+		if g.FleetOverview.ctxCancel != nil {
+			g.FleetOverview.ctxCancel()
+		}
+	case "files":
+		log.Println("Unselected: ", uid)
+		g.Files.close()
+	case "forwards":
+		log.Println("Unselected: ", uid)
+		if g.Forwards.ctxCancel != nil {
+			g.Forwards.ctxCancel()
+		}
+	case "signing":
+		log.Println("Unselected: ", uid)
+		if g.Signing.ctxCancel != nil {
+			g.Signing.ctxCancel()
+		}
+	case "alerts":
+		log.Println("Unselected: ", uid)
+		if g.AlertsView.ctxCancel != nil {
+			g.AlertsView.ctxCancel()
+		}
+	}
+}
+
+func (g *Gui) refreshCurrentTab() {
+	if g.currentTab.View != nil && g.setTab != nil {
+		g.setTab(g.currentTab)
+	}
+}
@@ -0,0 +1,309 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/alerting"
+)
+
+const alertSilenceDuration = time.Hour
+
+type alertsScreen struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+func makeAlertsScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
+	g.AlertsView.ctx, g.AlertsView.ctxCancel = context.WithCancel(context.Background())
+	engine := g.Alerts
+
+	var mu sync.Mutex
+	var active []alerting.Alert
+	var history []alerting.Notification
+	var cfg alerting.Config
+
+	saveConfig := func(update func(cfg *alerting.Config)) {
+		newCfg := engine.Config()
+		update(&newCfg)
+		if err := engine.SetConfig(newCfg); err != nil {
+			g.showErrorDialog(err, binding.NewDataListener(func() {}))
+		}
+	}
+
+	activeList := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(active)
+		},
+		func() fyne.CanvasObject {
+			text := widget.NewLabel("Template Object")
+			text.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("Silence 1h", theme.VolumeMuteIcon(), func() {}), text)
+		},
+		nil,
+	)
+	historyList := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(history)
+		},
+		func() fyne.CanvasObject {
+			text := widget.NewLabel("Template Object")
+			text.Truncation = fyne.TextTruncateEllipsis
+			return text
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			mu.Lock()
+			if id >= len(history) {
+				mu.Unlock()
+				return
+			}
+			n := history[id]
+			mu.Unlock()
+
+			text := fmt.Sprintf("%v  %v", n.Time.Local().Format(time.DateTime), n)
+			if n.Silenced {
+				text += " (silenced)"
+			}
+			label := item.(*widget.Label)
+			label.Importance = widget.MediumImportance
+			if n.Resolved {
+				label.Importance = widget.SuccessImportance
+			} else if n.Severity == alerting.Critical {
+				label.Importance = widget.DangerImportance
+			}
+			label.SetText(text)
+		},
+	)
+	webhookList := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(cfg.Webhooks)
+		},
+		func() fyne.CanvasObject {
+			text := widget.NewLabel("Template Object")
+			text.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {}), text)
+		},
+		nil,
+	)
+	silenceList := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(cfg.Silences)
+		},
+		func() fyne.CanvasObject {
+			text := widget.NewLabel("Template Object")
+			text.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {}), text)
+		},
+		nil,
+	)
+
+	refresh := func() {
+		newActive, newHistory, newCfg := engine.Active(), engine.History(), engine.Config()
+		mu.Lock()
+		active, history, cfg = newActive, newHistory, newCfg
+		mu.Unlock()
+		activeList.Refresh()
+		historyList.Refresh()
+		webhookList.Refresh()
+		silenceList.Refresh()
+	}
+
+	activeList.UpdateItem = func(id widget.ListItemID, item fyne.CanvasObject) {
+		mu.Lock()
+		if id >= len(active) {
+			mu.Unlock()
+			return
+		}
+		a := active[id]
+		mu.Unlock()
+
+		row := item.(*fyne.Container)
+		label := row.Objects[0].(*widget.Label)
+		silenceButton := row.Objects[1].(*widget.Button)
+		label.Importance = widget.WarningImportance
+		if a.Severity == alerting.Critical {
+			label.Importance = widget.DangerImportance
+		}
+		label.SetText(fmt.Sprintf("[%v] %v: %v (since %v)", a.Severity, a.Host, a.Message, a.Since.Local().Format(time.DateTime)))
+		silenceButton.OnTapped = func() {
+			if err := engine.Silence(a.Host, a.Rule, alertSilenceDuration); err != nil {
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+			}
+			log.Printf("Alert <%v> of <%v> silenced for %v", a.Rule, a.Host, alertSilenceDuration)
+			refresh()
+		}
+	}
+	webhookList.UpdateItem = func(id widget.ListItemID, item fyne.CanvasObject) {
+		mu.Lock()
+		if id >= len(cfg.Webhooks) {
+			mu.Unlock()
+			return
+		}
+		w := cfg.Webhooks[id]
+		mu.Unlock()
+
+		row := item.(*fyne.Container)
+		row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%v (%v): %v", w.Name, w.Format, w.URL))
+		row.Objects[1].(*widget.Button).OnTapped = func() {
+			saveConfig(func(cfg *alerting.Config) {
+				cfg.Webhooks = slices.DeleteFunc(cfg.Webhooks, func(x alerting.Webhook) bool { return x == w })
+			})
+			refresh()
+		}
+	}
+	silenceList.UpdateItem = func(id widget.ListItemID, item fyne.CanvasObject) {
+		mu.Lock()
+		if id >= len(cfg.Silences) {
+			mu.Unlock()
+			return
+		}
+		s := cfg.Silences[id]
+		mu.Unlock()
+
+		host, rule := s.Host, s.Rule
+		if host == "" {
+			host = "any host"
+		}
+		if rule == "" {
+			rule = "any rule"
+		}
+		row := item.(*fyne.Container)
+		row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%v, %v until %v", host, rule, s.Until.Local().Format(time.DateTime)))
+		row.Objects[1].(*widget.Button).OnTapped = func() {
+			saveConfig(func(cfg *alerting.Config) {
+				cfg.Silences = slices.DeleteFunc(cfg.Silences, func(x alerting.Silence) bool { return x == s })
+			})
+			refresh()
+		}
+	}
+
+	webhookName := widget.NewEntry()
+	webhookName.SetPlaceHolder("name")
+	webhookURL := widget.NewEntry()
+	webhookURL.SetPlaceHolder("https://hooks.slack.com/services/...")
+	formats := make([]string, len(alerting.WebhookFormats))
+	for i, f := range alerting.WebhookFormats {
+		formats[i] = string(f)
+	}
+	webhookFormat := widget.NewSelect(formats, func(string) {})
+	webhookFormat.SetSelected(string(alerting.SlackWebhook))
+	addWebhookButton := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		w := alerting.Webhook{
+			Name:   strings.TrimSpace(webhookName.Text),
+			URL:    strings.TrimSpace(webhookURL.Text),
+			Format: alerting.WebhookFormat(webhookFormat.Selected),
+		}
+		if err := w.Validate(); err != nil {
+			g.showErrorDialog(err, binding.NewDataListener(func() {}))
+			return
+		}
+		saveConfig(func(cfg *alerting.Config) { cfg.Webhooks = append(cfg.Webhooks, w) })
+		webhookName.SetText("")
+		webhookURL.SetText("")
+		refresh()
+	})
+
+	initialCfg := engine.Config()
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText(strconv.Itoa(initialCfg.MischanceThreshold))
+	thresholdEntry.Validator = func(s string) error {
+		if v, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || v < 0 {
+			return fmt.Errorf("threshold has to be a non negative number")
+		}
+		return nil
+	}
+	thresholdEntry.OnSubmitted = func(s string) {
+		if thresholdEntry.Validate() != nil {
+			return
+		}
+		v, _ := strconv.Atoi(strings.TrimSpace(s))
+		saveConfig(func(cfg *alerting.Config) { cfg.MischanceThreshold = v })
+	}
+
+	// signing rule is raised by signing monitor, it can be disabled as any other rule
+	ruleNames := []string{}
+	for _, r := range engine.Rules() {
+		ruleNames = append(ruleNames, r.Name)
+	}
+	ruleNames = append(ruleNames, alerting.RuleSigningMissRate)
+	enabled := []string{}
+	for _, name := range ruleNames {
+		if initialCfg.RuleEnabled(name) {
+			enabled = append(enabled, name)
+		}
+	}
+	rulesCheck := widget.NewCheckGroup(ruleNames, nil)
+	rulesCheck.SetSelected(enabled)
+	rulesCheck.OnChanged = func(selected []string) {
+		saveConfig(func(cfg *alerting.Config) {
+			cfg.DisabledRules = nil
+			for _, name := range ruleNames {
+				if !slices.Contains(selected, name) {
+					cfg.DisabledRules = append(cfg.DisabledRules, name)
+				}
+			}
+		})
+		refresh()
+	}
+
+	go func(ctx context.Context) {
+		refreshTime := 5 * time.Second
+		log.Printf("Starting alerts refresh goroutine with refresh rate %v", refreshTime)
+		ticker := time.NewTicker(refreshTime)
+		defer ticker.Stop()
+
+		refresh()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Ending alerts refresh goroutine")
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}(g.AlertsView.ctx)
+
+	settings := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(widget.NewFormItem("Mischance threshold:", thresholdEntry)),
+			widget.NewLabel("Enabled rules"),
+			rulesCheck,
+			widget.NewSeparator(),
+			widget.NewLabel("Webhooks"),
+			widget.NewForm(
+				widget.NewFormItem("Name", webhookName),
+				widget.NewFormItem("URL", webhookURL),
+				widget.NewFormItem("Format", webhookFormat),
+			),
+			addWebhookButton,
+		),
+		nil, nil, nil,
+		container.NewVSplit(webhookList, container.NewBorder(widget.NewLabel("Silences"), nil, nil, nil, silenceList)),
+	)
+
+	return container.NewAppTabs(
+		container.NewTabItem("Active", activeList),
+		container.NewTabItem("History", historyList),
+		container.NewTabItem("Settings", settings),
+	)
+}
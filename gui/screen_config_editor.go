@@ -1,25 +1,27 @@
 package gui
 
 import (
+	"context"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/widget"
-	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
 )
 
 func makeCfgEditorScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	appTomlTab := container.NewTabItem("app.toml", makeTextEditTab(
 		g,
 		func(cfg string) error {
-			err := httph.SetAppTomlConfig(g.sshClient, cfg, 8282)
+			err := g.shidai().SetConfig(context.Background(), shidai.AppToml, cfg)
 			if err != nil {
 				return err
 			}
 			return nil
 		},
 		func() (string, error) {
-			cfg, err := httph.GetAppTomlConfig(g.sshClient, 8282)
+			cfg, err := g.shidai().Config(context.Background(), shidai.AppToml)
 			if err != nil {
 				return "", err
 			}
@@ -29,14 +31,14 @@ func makeCfgEditorScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	configTomlTab := container.NewTabItem("config.toml", makeTextEditTab(
 		g,
 		func(cfg string) error {
-			err := httph.SetConfigTomlConfig(g.sshClient, cfg, 8282)
+			err := g.shidai().SetConfig(context.Background(), shidai.ConfigToml, cfg)
 			if err != nil {
 				return err
 			}
 			return nil
 		},
 		func() (string, error) {
-			cfg, err := httph.GetConfigTomlConfig(g.sshClient, 8282)
+			cfg, err := g.shidai().Config(context.Background(), shidai.ConfigToml)
 			if err != nil {
 				return "", err
 			}
@@ -0,0 +1,296 @@
+package gui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	dialogWizard "github.com/KiraCore/kensho/gui/dialogs"
+	"github.com/KiraCore/kensho/helper/gssh"
+)
+
+type filesScreen struct {
+	mu   sync.Mutex
+	sftp *gssh.SFTP
+}
+
+// returns sftp session of the selected host, session is opened on first use and closed when tab is left
+func (f *filesScreen) session(g *Gui) (*gssh.SFTP, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sftp != nil {
+		return f.sftp, nil
+	}
+	s, err := gssh.OpenSFTP(g.sshClient)
+	if err != nil {
+		return nil, err
+	}
+	f.sftp = s
+	return s, nil
+}
+
+func (f *filesScreen) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sftp != nil {
+		f.sftp.Close()
+		f.sftp = nil
+	}
+}
+
+func makeFilesScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
+	g.Files.close()
+
+	var mu sync.Mutex
+	var files []gssh.RemoteFile
+	currentDir := ""
+
+	pathEntry := widget.NewEntry()
+	statusLabel := widget.NewLabel("")
+
+	var list *widget.List
+	var openDir func(dir string)
+
+	// runs sftp operation with wait dialog and refreshes current directory after it
+	runOperation := func(op func(s *gssh.SFTP) error) {
+		s, err := g.Files.session(g)
+		if err != nil {
+			g.showErrorDialog(err, binding.NewDataListener(func() {}))
+			return
+		}
+		g.WaitDialog.ShowWaitDialog()
+		err = op(s)
+		g.WaitDialog.HideWaitDialog()
+		if err != nil {
+			g.showErrorDialog(err, binding.NewDataListener(func() {}))
+		}
+		openDir(currentDir)
+	}
+
+	openDir = func(dir string) {
+		s, err := g.Files.session(g)
+		if err != nil {
+			statusLabel.SetText(fmt.Sprintf("Unable to open SFTP session: %v", err))
+			return
+		}
+		if dir == "" {
+			dir, err = s.HomeDir()
+			if err != nil {
+				statusLabel.SetText(err.Error())
+				return
+			}
+		}
+		newFiles, err := s.List(dir)
+		if err != nil {
+			statusLabel.SetText(err.Error())
+			pathEntry.SetText(currentDir)
+			return
+		}
+		mu.Lock()
+		files = newFiles
+		currentDir = dir
+		mu.Unlock()
+		pathEntry.SetText(dir)
+		statusLabel.SetText(fmt.Sprintf("%v entries", len(newFiles)))
+		list.UnselectAll()
+		list.Refresh()
+	}
+
+	showActions := func(file gssh.RemoteFile) {
+		var wizard *dialogWizard.Wizard
+		downloadButton := widget.NewButtonWithIcon("Download", theme.DownloadIcon(), func() {
+			wizard.Hide()
+			saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if writer == nil {
+					return
+				}
+				localPath := writer.URI().Path()
+				writer.Close()
+				go showTransfersDialog(g, "Downloading", []gssh.Transfer{{Direction: gssh.Download, LocalPath: localPath, RemotePath: file.Path}})
+			}, g.Window)
+			saveDialog.SetFileName(file.Name)
+			saveDialog.Show()
+		})
+		if file.IsDir {
+			downloadButton.Disable()
+		}
+		renameButton := widget.NewButtonWithIcon("Rename / move", theme.DocumentCreateIcon(), func() {
+			wizard.Hide()
+			showFileEntryDialog(g, "Rename", "New path", file.Path, func(newPath string) {
+				if !path.IsAbs(newPath) {
+					newPath = path.Join(currentDir, newPath)
+				}
+				go runOperation(func(s *gssh.SFTP) error { return s.Rename(file.Path, newPath) })
+			})
+		})
+		chmodButton := widget.NewButtonWithIcon("Permissions", theme.SettingsIcon(), func() {
+			wizard.Hide()
+			showFileEntryDialog(g, "Permissions", "Octal mode, e.g. 644", fmt.Sprintf("%o", file.Mode.Perm()), func(value string) {
+				mode, err := strconv.ParseUint(value, 8, 32)
+				if err != nil || mode > 0o777 {
+					g.showErrorDialog(fmt.Errorf("mode <%v> is not valid octal permission", value), binding.NewDataListener(func() {}))
+					return
+				}
+				go runOperation(func(s *gssh.SFTP) error { return s.Chmod(file.Path, os.FileMode(mode)) })
+			})
+		})
+		deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+			wizard.Hide()
+			msg := fmt.Sprintf("Delete <%v>?", file.Path)
+			if file.IsDir {
+				msg = fmt.Sprintf("Delete directory <%v> with all its content?", file.Path)
+			}
+			showWarningMessageWithConfirmation(g, msg, binding.NewDataListener(func() {
+				go runOperation(func(s *gssh.SFTP) error { return s.Delete(file.Path) })
+			}))
+		})
+		deleteButton.Importance = widget.DangerImportance
+		closeButton := widget.NewButton("Close", func() { wizard.Hide() })
+
+		info := widget.NewLabel(fmt.Sprintf("%v\nSize: %v\nMode: %v\nModified: %v",
+			file.Path, formatFileSize(file.Size), file.Mode, file.ModTime.Format(time.DateTime)))
+		info.Wrapping = fyne.TextWrapBreak
+		content := container.NewVBox(info, downloadButton, renameButton, chmodButton, deleteButton, closeButton)
+		wizard = dialogWizard.NewWizard(file.Name, content)
+		wizard.Show(g.Window)
+		wizard.Resize(fyne.NewSize(400, 350))
+	}
+
+	list = widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(files)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("Template Object")
+			name.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil,
+				widget.NewIcon(theme.FileIcon()),
+				container.NewHBox(widget.NewLabel("size"), widget.NewLabel("mode"), widget.NewLabel("time"), widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {})),
+				name,
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			mu.Lock()
+			if id >= len(files) {
+				mu.Unlock()
+				return
+			}
+			file := files[id]
+			mu.Unlock()
+
+			row := item.(*fyne.Container)
+			name := row.Objects[0].(*widget.Label)
+			icon := row.Objects[1].(*widget.Icon)
+			details := row.Objects[2].(*fyne.Container)
+
+			name.SetText(file.Name)
+			if file.IsDir {
+				icon.SetResource(theme.FolderIcon())
+				details.Objects[0].(*widget.Label).SetText("")
+			} else {
+				icon.SetResource(theme.FileIcon())
+				details.Objects[0].(*widget.Label).SetText(formatFileSize(file.Size))
+			}
+			details.Objects[1].(*widget.Label).SetText(file.Mode.String())
+			details.Objects[2].(*widget.Label).SetText(file.ModTime.Format(time.DateTime))
+			details.Objects[3].(*widget.Button).OnTapped = func() { showActions(file) }
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		mu.Lock()
+		if id >= len(files) {
+			mu.Unlock()
+			return
+		}
+		file := files[id]
+		mu.Unlock()
+		list.Unselect(id)
+		if file.IsDir {
+			go openDir(file.Path)
+			return
+		}
+		showActions(file)
+	}
+
+	pathEntry.OnSubmitted = func(dir string) { go openDir(dir) }
+	upButton := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		go openDir(path.Dir(currentDir))
+	})
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		go openDir(currentDir)
+	})
+	mkdirButton := widget.NewButtonWithIcon("", theme.FolderNewIcon(), func() {
+		showFileEntryDialog(g, "New folder", "Folder name", "", func(name string) {
+			go runOperation(func(s *gssh.SFTP) error { return s.Mkdir(path.Join(currentDir, name)) })
+		})
+	})
+	uploadButton := widget.NewButtonWithIcon("Upload", theme.UploadIcon(), func() {
+		dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if reader == nil {
+				return
+			}
+			localPath := reader.URI().Path()
+			remotePath := path.Join(currentDir, reader.URI().Name())
+			reader.Close()
+			go func() {
+				showTransfersDialog(g, "Uploading", []gssh.Transfer{{Direction: gssh.Upload, LocalPath: localPath, RemotePath: remotePath}})
+				openDir(currentDir)
+			}()
+		}, g.Window).Show()
+	})
+	// uploads every regular file of local folder (not recursive) in parallel
+	uploadFolderButton := widget.NewButtonWithIcon("Upload folder", theme.FolderOpenIcon(), func() {
+		dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if uri == nil {
+				return
+			}
+			entries, err := os.ReadDir(uri.Path())
+			if err != nil {
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				return
+			}
+			var transfers []gssh.Transfer
+			for _, e := range entries {
+				if !e.Type().IsRegular() {
+					continue
+				}
+				transfers = append(transfers, gssh.Transfer{
+					Direction:  gssh.Upload,
+					LocalPath:  filepath.Join(uri.Path(), e.Name()),
+					RemotePath: path.Join(currentDir, e.Name()),
+				})
+			}
+			if len(transfers) == 0 {
+				showInfoDialog(g, "Upload folder", fmt.Sprintf("<%v> has no files to upload", uri.Path()))
+				return
+			}
+			go func() {
+				showTransfersDialog(g, "Uploading", transfers)
+				openDir(currentDir)
+			}()
+		}, g.Window).Show()
+	})
+
+	log.Println("Opening files screen")
+	go openDir("")
+
+	toolbar := container.NewBorder(nil, nil,
+		upButton,
+		container.NewHBox(refreshButton, mkdirButton, uploadButton, uploadFolderButton),
+		pathEntry,
+	)
+	return container.NewBorder(toolbar, statusLabel, nil, nil, list)
+}
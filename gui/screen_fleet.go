@@ -0,0 +1,322 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+type fleetOverviewScreen struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+type nodeHealth int
+
+const (
+	healthUnknown nodeHealth = iota
+	healthOK
+	healthWarning
+	healthCritical
+)
+
+func (h nodeHealth) String() string {
+	switch h {
+	case healthOK:
+		return "OK"
+	case healthWarning:
+		return "Warning"
+	case healthCritical:
+		return "Critical"
+	default:
+		return "Unknown"
+	}
+}
+
+func (h nodeHealth) importance() widget.Importance {
+	switch h {
+	case healthOK:
+		return widget.SuccessImportance
+	case healthWarning:
+		return widget.WarningImportance
+	case healthCritical:
+		return widget.DangerImportance
+	default:
+		return widget.LowImportance
+	}
+}
+
+type fleetRow struct {
+	Name      string
+	Dashboard *shidai.Dashboard
+	Err       error
+	Health    nodeHealth
+	Latency   time.Duration
+}
+
+// returns node health based on dashboard values
+func getNodeHealth(d *shidai.Dashboard, err error) nodeHealth {
+	if err != nil || d == nil {
+		return healthCritical
+	}
+	switch strings.ToUpper(d.ValidatorStatus) {
+	case string(shidai.Jailed), string(shidai.Inactive):
+		return healthCritical
+	case string(shidai.Paused):
+		return healthWarning
+	}
+	if d.CatchingUp {
+		return healthWarning
+	}
+	if mischance, err := strconv.Atoi(d.Mischance); err == nil && mischance > 0 {
+		return healthWarning
+	}
+	return healthOK
+}
+
+type fleetColumn struct {
+	Title string
+	Width float32
+	Value func(r fleetRow) string
+}
+
+var fleetColumns = []fleetColumn{
+	{Title: "Host", Width: 180, Value: func(r fleetRow) string { return r.Name }},
+	{Title: "Health", Width: 90, Value: func(r fleetRow) string { return r.Health.String() }},
+	{Title: "Latency", Width: 90, Value: func(r fleetRow) string {
+		if r.Latency == 0 {
+			return "-"
+		}
+		return strconv.FormatInt(r.Latency.Milliseconds(), 10) + " ms"
+	}},
+	{Title: "Moniker", Width: 140, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.Moniker })
+	}},
+	{Title: "Val.Status", Width: 100, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.ValidatorStatus })
+	}},
+	{Title: "Node", Width: 90, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string {
+			if d.CatchingUp {
+				return "Syncing"
+			}
+			return "Running"
+		})
+	}},
+	{Title: "Block", Width: 100, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.Blocks })
+	}},
+	{Title: "Streak", Width: 90, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.Streak })
+	}},
+	{Title: "Miss", Width: 80, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.Mischance })
+	}},
+	{Title: "Produced", Width: 100, Value: func(r fleetRow) string {
+		return dashboardValue(r, func(d *shidai.Dashboard) string { return d.ProducedBlocks })
+	}},
+	{Title: "Error", Width: 300, Value: func(r fleetRow) string {
+		if r.Err != nil {
+			return r.Err.Error()
+		}
+		return ""
+	}},
+}
+
+func dashboardValue(r fleetRow, get func(d *shidai.Dashboard) string) string {
+	if r.Dashboard == nil {
+		return "-"
+	}
+	return get(r.Dashboard)
+}
+
+// compares numerically if both values are numbers (latency in ms included)
+func lessFleetValue(a, b string) bool {
+	aInt, errA := strconv.Atoi(strings.TrimSuffix(a, " ms"))
+	bInt, errB := strconv.Atoi(strings.TrimSuffix(b, " ms"))
+	if errA == nil && errB == nil {
+		return aInt < bInt
+	}
+	return a < b
+}
+
+// polls /dashboard of every fleet host in parallel
+func getFleetDashboards(g *Gui) []fleetRow {
+	connections := g.Fleet.List()
+	rows := make([]fleetRow, len(connections))
+
+	var wg sync.WaitGroup
+	for i, c := range connections {
+		wg.Add(1)
+		go func(i int, c *HostConnection) {
+			defer wg.Done()
+			row := fleetRow{Name: c.Name}
+			if g.Fleet.IsConnected(c) {
+				row.Latency = g.Fleet.Latency(c)
+			}
+			if !g.Fleet.IsConnected(c) {
+				row.Err = fmt.Errorf("not connected")
+				if attempt := g.Fleet.ReconnectAttempt(c); attempt > 0 {
+					row.Err = fmt.Errorf("not connected, reconnecting (attempt %v)", attempt)
+				}
+			} else {
+				row.Dashboard, row.Err = shidaiclient.New(g.Fleet.Client(c), c.Host.ShidaiPort).Dashboard(context.Background())
+			}
+			row.Health = getNodeHealth(row.Dashboard, row.Err)
+			rows[i] = row
+		}(i, c)
+	}
+	wg.Wait()
+	return rows
+}
+
+func makeFleetOverviewScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
+	g.FleetOverview.ctx, g.FleetOverview.ctxCancel = context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var rows []fleetRow
+	sortColumn := 0
+	sortAsc := true
+
+	sortRows := func() {
+		column := fleetColumns[sortColumn]
+		sort.SliceStable(rows, func(i, j int) bool {
+			if sortAsc {
+				return lessFleetValue(column.Value(rows[i]), column.Value(rows[j]))
+			}
+			return lessFleetValue(column.Value(rows[j]), column.Value(rows[i]))
+		})
+	}
+
+	table := widget.NewTable(
+		func() (int, int) {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(rows), len(fleetColumns)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("Template Object")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, item fyne.CanvasObject) {
+			mu.Lock()
+			defer mu.Unlock()
+			label := item.(*widget.Label)
+			if id.Row >= len(rows) {
+				label.SetText("")
+				return
+			}
+			row := rows[id.Row]
+			label.Importance = widget.MediumImportance
+			if fleetColumns[id.Col].Title == "Health" {
+				label.Importance = row.Health.importance()
+				label.TextStyle.Bold = true
+			} else {
+				label.TextStyle.Bold = false
+			}
+			label.SetText(fleetColumns[id.Col].Value(row))
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewButton("Template Object", func() {})
+	}
+	table.UpdateHeader = func(id widget.TableCellID, item fyne.CanvasObject) {
+		button := item.(*widget.Button)
+		if id.Col < 0 {
+			button.SetText("")
+			return
+		}
+		title := fleetColumns[id.Col].Title
+		if id.Col == sortColumn {
+			if sortAsc {
+				title += " ▲"
+			} else {
+				title += " ▼"
+			}
+		}
+		button.SetText(title)
+		button.OnTapped = func() {
+			mu.Lock()
+			if sortColumn == id.Col {
+				sortAsc = !sortAsc
+			} else {
+				sortColumn = id.Col
+				sortAsc = true
+			}
+			sortRows()
+			mu.Unlock()
+			table.Refresh()
+		}
+	}
+	for i, column := range fleetColumns {
+		table.SetColumnWidth(i, column.Width)
+	}
+
+	// drill-down into node info of the selected host
+	table.OnSelected = func(id widget.TableCellID) {
+		mu.Lock()
+		if id.Row >= len(rows) {
+			mu.Unlock()
+			return
+		}
+		name := rows[id.Row].Name
+		mu.Unlock()
+		table.UnselectAll()
+		log.Printf("Fleet overview drill-down into <%v>", name)
+		g.selectHost(name)
+		g.navTree.Select("nodeInfo")
+	}
+
+	lastUpdateLabel := widget.NewLabel("")
+	refresh := func() {
+		newRows := getFleetDashboards(g)
+		mu.Lock()
+		rows = newRows
+		sortRows()
+		mu.Unlock()
+		lastUpdateLabel.SetText(fmt.Sprintf("Hosts: %v, last update: %v", len(newRows), time.Now().Format(time.TimeOnly)))
+		table.Refresh()
+	}
+
+	go func(ctx context.Context) {
+		refreshTime := 20 * time.Second
+		log.Printf("Starting fleet overview goroutine with refresh rate %v", refreshTime)
+		ticker := time.NewTicker(refreshTime)
+		defer ticker.Stop()
+
+		refresh()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Ending fleet overview refresh goroutine")
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}(g.FleetOverview.ctx)
+
+	refreshButton := widget.NewButton("Refresh", func() {
+		// polling every host takes a while, keep the UI responsive
+		go func() {
+			g.WaitDialog.ShowWaitDialog()
+			refresh()
+			g.WaitDialog.HideWaitDialog()
+		}()
+	})
+
+	return container.NewBorder(lastUpdateLabel, refreshButton, nil, nil, table)
+}
@@ -0,0 +1,178 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/types"
+)
+
+type forwardsScreen struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+// local forwards of node services, local port is the same as the remote one
+func getForwardPresets(h *Host) []gssh.ForwardSpec {
+	preset := func(name string, port int) gssh.ForwardSpec {
+		return gssh.ForwardSpec{
+			Name:       name,
+			Kind:       gssh.LocalForward,
+			LocalAddr:  net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+			RemoteAddr: net.JoinHostPort("localhost", strconv.Itoa(port)),
+		}
+	}
+	return []gssh.ForwardSpec{
+		preset("Interx", h.InterxPort),
+		preset("Sekai RPC", h.RPCPort),
+		preset("Sekai gRPC", types.DEFAULT_GRPC_PORT),
+		preset("Shidai", h.ShidaiPort),
+	}
+}
+
+func makeForwardsScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
+	g.Forwards.ctx, g.Forwards.ctxCancel = context.WithCancel(context.Background())
+
+	c := g.Fleet.Selected()
+	if c == nil || c.Forwards == nil {
+		return widget.NewLabel("Not connected")
+	}
+	manager := c.Forwards
+
+	var mu sync.Mutex
+	var forwards []gssh.ForwardStatus
+
+	var list *widget.List
+	refresh := func() {
+		newForwards := manager.List()
+		mu.Lock()
+		forwards = newForwards
+		mu.Unlock()
+		list.Refresh()
+	}
+
+	addForward := func(spec gssh.ForwardSpec) {
+		err := manager.Add(spec)
+		if err != nil {
+			g.showErrorDialog(err, binding.NewDataListener(func() {}))
+		}
+		refresh()
+	}
+
+	list = widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(forwards)
+		},
+		func() fyne.CanvasObject {
+			spec := widget.NewLabel("Template Object")
+			spec.Truncation = fyne.TextTruncateEllipsis
+			status := widget.NewLabel("status")
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(status, widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {})),
+				spec,
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			mu.Lock()
+			if id >= len(forwards) {
+				mu.Unlock()
+				return
+			}
+			f := forwards[id]
+			mu.Unlock()
+
+			row := item.(*fyne.Container)
+			specLabel := row.Objects[0].(*widget.Label)
+			right := row.Objects[1].(*fyne.Container)
+			statusLabel := right.Objects[0].(*widget.Label)
+			removeButton := right.Objects[1].(*widget.Button)
+
+			text := f.Spec.String()
+			if f.Spec.Name != "" {
+				text = fmt.Sprintf("%v: %v", f.Spec.Name, text)
+			}
+			specLabel.SetText(text)
+			switch {
+			case f.Err != nil:
+				statusLabel.Importance = widget.DangerImportance
+				statusLabel.SetText(fmt.Sprintf("Error: %v", f.Err))
+			case f.Active:
+				statusLabel.Importance = widget.SuccessImportance
+				statusLabel.SetText(fmt.Sprintf("Active, %v conn.", f.Connections))
+			default:
+				statusLabel.Importance = widget.WarningImportance
+				statusLabel.SetText("Stopped")
+			}
+			removeButton.OnTapped = func() {
+				manager.Remove(f.Spec)
+				refresh()
+			}
+		},
+	)
+
+	presetButtons := container.NewHBox()
+	for _, p := range getForwardPresets(g.Host) {
+		presetButtons.Add(widget.NewButton(fmt.Sprintf("%v (%v)", p.Name, p.LocalAddr), func() { addForward(p) }))
+	}
+
+	kindSelect := widget.NewSelect([]string{string(gssh.LocalForward), string(gssh.RemoteForward)}, func(string) {})
+	kindSelect.SetSelected(string(gssh.LocalForward))
+	localEntry := widget.NewEntry()
+	localEntry.SetPlaceHolder("127.0.0.1:8080")
+	remoteEntry := widget.NewEntry()
+	remoteEntry.SetPlaceHolder("localhost:8080")
+	addButton := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+		addForward(gssh.ForwardSpec{
+			Kind:       gssh.ForwardKind(kindSelect.Selected),
+			LocalAddr:  localEntry.Text,
+			RemoteAddr: remoteEntry.Text,
+		})
+	})
+	customForm := widget.NewForm(
+		widget.NewFormItem("Kind", kindSelect),
+		widget.NewFormItem("Local address", localEntry),
+		widget.NewFormItem("Remote address", remoteEntry),
+	)
+
+	go func(ctx context.Context) {
+		refreshTime := 5 * time.Second
+		log.Printf("Starting port forwards refresh goroutine with refresh rate %v", refreshTime)
+		ticker := time.NewTicker(refreshTime)
+		defer ticker.Stop()
+
+		refresh()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Ending port forwards refresh goroutine")
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}(g.Forwards.ctx)
+
+	top := container.NewVBox(
+		widget.NewLabel("Presets"),
+		container.NewHScroll(presetButtons),
+		widget.NewSeparator(),
+		widget.NewLabel("Custom forward"),
+		customForm,
+		addButton,
+		widget.NewSeparator(),
+	)
+	return container.NewBorder(top, nil, nil, nil, list)
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"io"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
 	"sync"
@@ -16,7 +15,7 @@ import (
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
 	"github.com/atotto/clipboard"
 )
 
@@ -38,13 +37,13 @@ var (
 func makeLogScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 
 	sekaiTab := container.NewTabItem("Sekai",
-		makeLogTab(g, "http://localhost:8282/logs/sekai", SEKAI_LOG),
+		makeLogTab(g, shidaiclient.SekaiLogs, SEKAI_LOG),
 	)
 	interxTab := container.NewTabItem("Interx",
-		makeLogTab(g, "http://localhost:8282/logs/interx", INTERX_LOG),
+		makeLogTab(g, shidaiclient.InterxLogs, INTERX_LOG),
 	)
 	shidaiTab := container.NewTabItem("Shidai",
-		makeLogTab(g, "http://localhost:8282/logs/shidai", SHIDAI_LOG),
+		makeLogTab(g, shidaiclient.ShidaiLogs, SHIDAI_LOG),
 	)
 
 	tabsMenu := container.NewAppTabs(sekaiTab, interxTab, shidaiTab)
@@ -57,7 +56,7 @@ func makeLogScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	return tabsMenu
 }
 
-func makeLogTab(g *Gui, addressToListen string, logType string) fyne.CanvasObject {
+func makeLogTab(g *Gui, component shidaiclient.LogComponent, logType string) fyne.CanvasObject {
 	g.LogCtx, g.LogCtxCancel = context.WithCancel(context.Background())
 
 	cancelAndCreateFunc := func() {
@@ -83,18 +82,18 @@ func makeLogTab(g *Gui, addressToListen string, logType string) fyne.CanvasObjec
 		cancelAndCreateFunc()
 	})
 	startStopFunc := func() {
-		resp, err := httph.CreateTunnelForSSEConnection(g.sshClient, addressToListen)
-		if err != nil {
-			log.Println(err)
-			return
-		}
 		state, _ := switchData.Get()
 		log.Println("state:", state)
 		if state {
+			stream, err := g.shidai().Logs(context.Background(), component)
+			if err != nil {
+				log.Println(err)
+				return
+			}
 			startStopButton.Text = "Stop"
 			switchData.Set(false)
 			g.LogCtx, g.LogCtxCancel = context.WithCancel(context.Background())
-			go runLogScreenV2(resp, g.LogCtx, logTextLabel, logType)
+			go runLogScreenV2(stream, g.LogCtx, logTextLabel, logType)
 		} else {
 			switchData.Set(true)
 			startStopButton.Text = "Start"
@@ -118,15 +117,15 @@ func makeLogTab(g *Gui, addressToListen string, logType string) fyne.CanvasObjec
 
 }
 
-func runLogScreenV2(resp *http.Response, cancelCtx context.Context, infoLabel *widget.Label, logType string) {
+func runLogScreenV2(stream io.ReadCloser, cancelCtx context.Context, infoLabel *widget.Label, logType string) {
 	var writeData string
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	wg.Add(2)
 
 	go func(ctx context.Context) {
-		reader := bufio.NewReader(resp.Body)
-		defer resp.Body.Close()
+		reader := bufio.NewReader(stream)
+		defer stream.Close()
 		defer wg.Done()
 		for {
 			select {
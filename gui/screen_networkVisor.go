@@ -12,7 +12,6 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/KiraCore/kensho/helper/networkparser"
-	"github.com/KiraCore/kensho/types"
 	"github.com/atotto/clipboard"
 )
 
@@ -68,7 +67,7 @@ func makeNetworkTreeScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	})
 	refreshButton := widget.NewButton("Refresh", func() {
 		g.WaitDialog.ShowWaitDialog()
-		nodes, _, err = networkparser.GetAllNodesV3(context.Background(), g.Host.IP, types.DEFAULT_INTERX_PORT, 3, false)
+		nodes, _, err = networkparser.GetAllNodesV3(context.Background(), g.Host.IP, g.Host.InterxPort, 3, false)
 
 		// TODO: for testing
 		// nodes, _, err = networkparser.GetAllNodesV3(context.Background(), "148.251.69.56", types.DEFAULT_INTERX_PORT, 4, false)
@@ -2,10 +2,10 @@ package gui
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -15,11 +15,26 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"github.com/atotto/clipboard"
 
-	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/cometrpc"
+	"github.com/KiraCore/kensho/helper/metrics"
 	"github.com/KiraCore/kensho/types"
 	"github.com/KiraCore/kensho/types/endpoint/shidai"
 )
 
+// with live rpc subscription dashboard is refreshed on new blocks, but at most this often
+const nodeInfoLiveRefreshInterval = 10 * time.Second
+
+var nodeInfoHistoryRanges = []struct {
+	title    string
+	duration time.Duration
+}{
+	{"Last hour", time.Hour},
+	{"Last 6 hours", 6 * time.Hour},
+	{"Last 24 hours", 24 * time.Hour},
+	{"Last 7 days", 7 * 24 * time.Hour},
+	{"Last 30 days", 30 * 24 * time.Hour},
+}
+
 type nodeInfoScreen struct {
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -44,6 +59,10 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 	latestBlockData := binding.NewString()
 	latestBlockLabel := widget.NewLabelWithData(latestBlockData)
 
+	updatesData := binding.NewString()
+	updatesData.Set("Connecting to RPC...")
+	updatesLabel := widget.NewLabelWithData(updatesData)
+
 	// validator address box
 	validatorAddressData := binding.NewString()
 	validatorAddressLabel := widget.NewLabelWithData(validatorAddressData)
@@ -160,6 +179,7 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 		widget.NewFormItem("Val.Status:", validatorStatusLabel),
 		widget.NewFormItem("Node Status:", nodeCatchingLabel),
 		widget.NewFormItem("Block:", latestBlockLabel),
+		widget.NewFormItem("Updates:", updatesLabel),
 		widget.NewFormItem("Latest Block:", lastProducedLabel),
 		widget.NewFormItem("Produced:", producedBlocksLabel),
 		widget.NewFormItem("Streak:", streakLabel),
@@ -174,21 +194,15 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 	execFunc := func(args types.ExecSekaiMaintenanceCommands) {
 		g.TxExec.TxExecutionStatusBinding.Set(true)
 
-		request := types.RequestTXPayload{Command: "tx", Args: args}
-		payload, err := json.Marshal(request)
-
 		log.Printf("Executing: %+v", args)
-		if err != nil {
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-		}
-		out, err := httph.ExecHttpRequestBySSHTunnel(g.sshClient, types.SEKIN_EXECUTE_ENDPOINT, "POST", payload)
+		out, err := g.shidai().Tx(context.Background(), args)
 		if err != nil {
 			log.Println("ERROR when executing payload:", err.Error())
 			g.showErrorDialog(err, binding.NewDataListener(func() {}))
 			return
 		}
 
-		log.Println("payload execution out:", string(out))
+		log.Println("payload execution out:", out.Output)
 		refreshBinding.DataChanged()
 	}
 
@@ -217,10 +231,37 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 
 	errBinding := binding.NewUntyped()
 
-	refreshScreen := func() {
-		g.WaitDialog.ShowWaitDialog()
-		defer g.WaitDialog.HideWaitDialog()
-		dashboardData, err := httph.GetDashboardInfo(g.sshClient, 8282)
+	historyChart := newLineChart()
+	historyMetric := widget.NewSelect(nil, nil)
+	historyRange := widget.NewSelect(nil, nil)
+	metricTitles := map[string]metrics.Metric{}
+	for _, m := range metrics.Metrics {
+		metricTitles[m.Title()] = m
+		historyMetric.Options = append(historyMetric.Options, m.Title())
+	}
+	for _, r := range nodeInfoHistoryRanges {
+		historyRange.Options = append(historyRange.Options, r.title)
+	}
+	updateHistory := func() {
+		c := g.Fleet.Selected()
+		if c == nil {
+			return
+		}
+		since := time.Now().Add(-nodeInfoHistoryRanges[historyRange.SelectedIndex()].duration)
+		points, err := g.Metrics.Query(c.Name, metricTitles[historyMetric.Selected], since)
+		if err != nil {
+			log.Printf("Unable to load history: %v", err)
+		}
+		historyChart.SetPoints(points)
+	}
+	historyMetric.SetSelected(metrics.BlockHeight.Title())
+	historyRange.SetSelectedIndex(0)
+	historyMetric.OnChanged = func(string) { updateHistory() }
+	historyRange.OnChanged = func(string) { updateHistory() }
+
+	updateDashboard := func() {
+		updateHistory()
+		dashboardData, err := g.shidai().Dashboard(context.Background())
 		if err != nil {
 			errBinding.Set(fmt.Errorf("ERROR: getting dashboard info: %w", err))
 			return
@@ -322,6 +363,11 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 			nodeCatchingData.Set("Running")
 		}
 	}
+	refreshScreen := func() {
+		g.WaitDialog.ShowWaitDialog()
+		defer g.WaitDialog.HideWaitDialog()
+		updateDashboard()
+	}
 	refreshBinding = binding.NewDataListener(func() {
 		refreshScreen()
 		err, _ := errBinding.Get()
@@ -334,24 +380,65 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 	})
 	g.TxExec.TxDoneListener = binding.NewDataListener(func() { refreshBinding.DataChanged() })
 
-	go func() {
+	go func(ctx context.Context) {
 		refreshTime := 20 * time.Second
 		log.Printf("Starting goroutine with refresh rate %v", refreshTime)
 		timer := time.NewTimer(refreshTime)
 		defer timer.Stop() // Clean up the timer when the goroutine ends
 
 		refreshBinding.DataChanged()
+
+		// new blocks drive the refresh, timer is only fallback while rpc websocket is not available
+		// rpc follows the connection, so subscription comes back after reconnect
+		var live atomic.Bool
+		var events <-chan cometrpc.Event
+		if conn := g.Fleet.Selected(); conn != nil {
+			events = g.hostRPC(conn).Subscribe(ctx, []string{cometrpc.NewBlockQuery, cometrpc.TxQuery}, func(err error) {
+				live.Store(err == nil)
+				if err != nil {
+					updatesData.Set(fmt.Sprintf("Every %v (RPC unavailable: %v)", refreshTime, err))
+					return
+				}
+				updatesData.Set("Live")
+			})
+		}
+		var lastRefresh time.Time
+		// tx may change validator state, dashboard is refreshed with the next block
+		txSeen := false
 		for {
 			select {
-			case <-g.NodeInfo.ctx.Done():
+			case <-ctx.Done():
 				log.Printf("Ending nodeInfo refresh goroutine")
 				return
 			case <-timer.C:
-				refreshBinding.DataChanged()
+				if !live.Load() {
+					refreshBinding.DataChanged()
+				}
 				timer.Reset(refreshTime) // Reset the timer
+			case ev, ok := <-events:
+				if !ok {
+					// closed only after ctx is done
+					events = nil
+					continue
+				}
+				if ev.Query == cometrpc.TxQuery {
+					txSeen = true
+					continue
+				}
+				block, err := ev.NewBlock()
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				latestBlockData.Set(block.Block.Header.Height)
+				if txSeen || time.Since(lastRefresh) >= nodeInfoLiveRefreshInterval {
+					txSeen = false
+					lastRefresh = time.Now()
+					updateDashboard()
+				}
 			}
 		}
-	}()
+	}(g.NodeInfo.ctx)
 
 	// refreshButton := widget.NewButton("Refresh", refreshBinding.DataChanged)
 	// sendSekaiCommandButton := widget.NewButton("Execute sekai command", func() { showSekaiExecuteDialog(g) })
@@ -368,5 +455,15 @@ func makeNodeInfoTab(g *Gui) fyne.CanvasObject {
 	executeSekaiCmdButton := widget.NewButton("Execute sekai command", func() {
 		showSekaiExecuteDialog(g)
 	})
-	return container.NewBorder(container.NewCenter(validatorsTopPart), container.NewVBox(validatorControlButton, executeSekaiCmdButton), nil, nil, mainInfo)
+	history := container.NewBorder(
+		container.NewHBox(historyMetric, historyRange),
+		widget.NewLabel("Samples are collected in background every 30s while the host is connected"),
+		nil, nil,
+		historyChart,
+	)
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Info", mainInfo),
+		container.NewTabItem("History", history),
+	)
+	return container.NewBorder(container.NewCenter(validatorsTopPart), container.NewVBox(validatorControlButton, executeSekaiCmdButton), nil, nil, tabs)
 }
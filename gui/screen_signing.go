@@ -0,0 +1,139 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type signingScreen struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+func makeSigningScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
+	g.Signing.ctx, g.Signing.ctxCancel = context.WithCancel(context.Background())
+
+	c := g.Fleet.Selected()
+	if c == nil || c.Signing == nil {
+		return widget.NewLabel("Not connected")
+	}
+	monitor := c.Signing
+
+	addressLabel := widget.NewLabel("")
+	statusLabel := widget.NewLabel("")
+	summaryLabel := widget.NewLabel("")
+	lastMissedLabel := widget.NewLabel("")
+
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText(strconv.FormatFloat(monitor.Threshold()*100, 'f', -1, 64))
+	thresholdEntry.Validator = func(s string) error {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || v <= 0 || v > 100 {
+			return fmt.Errorf("threshold has to be a percentage between 0 and 100")
+		}
+		return nil
+	}
+	thresholdEntry.OnSubmitted = func(s string) {
+		if thresholdEntry.Validate() != nil {
+			return
+		}
+		v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		monitor.SetThreshold(v / 100)
+		log.Printf("Signing miss threshold of <%v> set to %v%%", c.Name, v)
+	}
+
+	// one cell per block of the window, oldest block top left
+	cells := make([]*canvas.Rectangle, monitor.Window.Size())
+	heatmap := container.NewGridWrap(fyne.NewSize(12, 12))
+	for i := range cells {
+		cells[i] = canvas.NewRectangle(theme.DisabledColor())
+		heatmap.Add(cells[i])
+	}
+
+	refresh := func() {
+		address := monitor.Address()
+		if address == "" {
+			address = "unknown"
+		}
+		addressLabel.SetText(address)
+
+		switch {
+		case monitor.Err() != nil:
+			statusLabel.Importance = widget.DangerImportance
+			statusLabel.SetText(fmt.Sprintf("RPC unavailable: %v", monitor.Err()))
+		case !monitor.InActiveSet():
+			statusLabel.Importance = widget.WarningImportance
+			statusLabel.SetText("Validator has no voting power, blocks are not recorded")
+		default:
+			statusLabel.Importance = widget.SuccessImportance
+			statusLabel.SetText("Following new blocks")
+		}
+
+		missed, total, rate := monitor.Window.MissRate()
+		summaryLabel.Importance = widget.MediumImportance
+		if total > 0 && rate >= monitor.Threshold() {
+			summaryLabel.Importance = widget.DangerImportance
+		}
+		summaryLabel.SetText(fmt.Sprintf("Signed %v of last %v blocks, missed %v (%.1f%%)", total-missed, total, missed, rate*100))
+
+		blocks := monitor.Window.Blocks()
+		lastMissed := "none"
+		// latest blocks are aligned to the end of the grid, empty cells are blocks not seen yet
+		offset := len(cells) - len(blocks)
+		for i, cell := range cells {
+			color := theme.DisabledColor()
+			if i >= offset {
+				b := blocks[i-offset]
+				if b.Signed {
+					color = theme.SuccessColor()
+				} else {
+					color = theme.ErrorColor()
+					lastMissed = fmt.Sprintf("%v (%v)", b.Height, b.Time.Local().Format(time.DateTime))
+				}
+			}
+			if cell.FillColor != color {
+				cell.FillColor = color
+				cell.Refresh()
+			}
+		}
+		lastMissedLabel.SetText(lastMissed)
+	}
+
+	go func(ctx context.Context) {
+		refreshTime := 2 * time.Second
+		log.Printf("Starting signing monitor refresh goroutine with refresh rate %v", refreshTime)
+		ticker := time.NewTicker(refreshTime)
+		defer ticker.Stop()
+
+		refresh()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Ending signing monitor refresh goroutine")
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}(g.Signing.ctx)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Validator:", addressLabel),
+		widget.NewFormItem("Status:", statusLabel),
+		widget.NewFormItem("Uptime:", summaryLabel),
+		widget.NewFormItem("Last missed:", lastMissedLabel),
+		widget.NewFormItem("Alert at miss rate %:", thresholdEntry),
+	)
+	legend := widget.NewLabel(fmt.Sprintf("Last %v blocks, green signed, red missed", len(cells)))
+	return container.NewBorder(container.NewVBox(form, widget.NewSeparator(), legend), nil, nil, nil, container.NewVScroll(heatmap))
+}
@@ -1,16 +1,19 @@
 package gui
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
 	"strconv"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	commandcontroller "github.com/KiraCore/kensho/helper/commandController"
 	"github.com/KiraCore/kensho/helper/httph"
-	"github.com/KiraCore/kensho/types"
 )
 
 func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
@@ -48,7 +51,7 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	deployButton.Disable()
 
 	checkInterxStatus := func() {
-		_, err := httph.GetInterxStatus(g.Host.IP, strconv.Itoa(types.DEFAULT_INTERX_PORT))
+		_, err := httph.GetInterxStatus(g.Host.IP, strconv.Itoa(g.Host.InterxPort))
 		if err != nil {
 			log.Printf("ERROR getting interx status: %v", err)
 			interxStatusInfo.SetText(STATUS_Unavailable)
@@ -62,7 +65,7 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	}
 
 	checkShidaiStatus := func() {
-		shidaiStatus, err := httph.GetShidaiStatus(g.sshClient, types.DEFAULT_SHIDAI_PORT)
+		shidaiStatus, err := g.shidai().Status(context.Background())
 		if err != nil {
 			log.Printf("ERROR: %v", err)
 			shidaiStatusInfo.SetText(STATUS_Unavailable)
@@ -81,7 +84,7 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 	}
 
 	checkSekaiStatus := func() {
-		_, err := httph.GetSekaiStatus(g.Host.IP, "26657")
+		_, err := httph.GetSekaiStatus(g.Host.IP, strconv.Itoa(g.Host.RPCPort))
 		if err != nil {
 			log.Printf("ERROR: %v", err)
 			sekaiStatusInfo.SetText(STATUS_Unavailable)
@@ -92,13 +95,25 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 			sekaiStatusCheck.Set(true)
 		}
 	}
+	controller := g.components()
+	componentRows := map[commandcontroller.Component]*componentRow{}
+	componentsBox := container.NewVBox()
+	// every component is checked with its own ssh sessions and health check, rows are updated when all are done
+	checkComponents := func() {
+		for _, s := range controller.StatusAll(context.Background()) {
+			componentRows[s.Component].update(s)
+		}
+	}
+
 	startButton := widget.NewButton("Start", func() {})
 	stopButton := widget.NewButton("Stop", func() {})
-	refresh := func() {
+	refreshChecks := func() {
 		g.WaitDialog.ShowWaitDialog()
+		defer g.WaitDialog.HideWaitDialog()
 		checkInterxStatus()
 		checkShidaiStatus()
 		checkSekaiStatus()
+		checkComponents()
 		shidaiCheck, _ := shidaiStatusCheck.Get()
 		sekaiCheck, _ := sekaiStatusCheck.Get()
 		interxCheck, _ := interxStatusCheck.Get()
@@ -136,62 +151,78 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 		} else {
 			deployButton.Enable()
 		}
-
-		defer g.WaitDialog.HideWaitDialog()
+	}
+	// checks run over network, so they are kept off the ui thread; refreshMu keeps overlapping refreshes in order
+	var refreshMu sync.Mutex
+	refresh := func() {
+		go func() {
+			refreshMu.Lock()
+			defer refreshMu.Unlock()
+			refreshChecks()
+		}()
 	}
 
 	//stop button logic
 	stopButton.OnTapped = func() {
-		g.WaitDialog.ShowWaitDialog()
-		var payloadStruct = types.RequestDeployPayload{
-			Command: "stop",
-		}
-		payload, err := json.Marshal(payloadStruct)
-		if err != nil {
-			log.Println("ERROR when executing payload:", err.Error())
-			g.WaitDialog.HideWaitDialog()
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-
-			return
-		}
-		out, err := httph.ExecHttpRequestBySSHTunnel(g.sshClient, types.SEKIN_EXECUTE_ENDPOINT, "POST", payload)
-		if err != nil {
-			log.Println("ERROR when executing payload:", err.Error())
+		go func() {
+			g.WaitDialog.ShowWaitDialog()
+			out, err := g.shidai().Stop(context.Background())
+			if err != nil {
+				log.Println("ERROR when executing stop:", err.Error())
+				g.WaitDialog.HideWaitDialog()
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				return
+			}
+			log.Println("STOP out:", out.Output)
 			g.WaitDialog.HideWaitDialog()
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-			return
-		}
-		log.Println("START out:", string(out))
-		g.WaitDialog.HideWaitDialog()
-		refresh()
+			refresh()
+		}()
 	}
 	stopButton.Disable()
 
 	//start button
 	startButton.OnTapped = func() {
-		g.WaitDialog.ShowWaitDialog()
-		var payloadStruct = types.RequestDeployPayload{
-			Command: "start",
-		}
-		payload, err := json.Marshal(payloadStruct)
-		if err != nil {
-			log.Println("ERROR when executing payload:", err.Error())
-			g.WaitDialog.HideWaitDialog()
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-			return
-		}
-		out, err := httph.ExecHttpRequestBySSHTunnel(g.sshClient, types.SEKIN_EXECUTE_ENDPOINT, "POST", payload)
-		if err != nil {
-			log.Println("ERROR when executing payload:", err.Error())
+		go func() {
+			g.WaitDialog.ShowWaitDialog()
+			out, err := g.shidai().Start(context.Background())
+			if err != nil {
+				log.Println("ERROR when executing start:", err.Error())
+				g.WaitDialog.HideWaitDialog()
+				g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				return
+			}
+			log.Println("START out:", out.Output)
 			g.WaitDialog.HideWaitDialog()
-			g.showErrorDialog(err, binding.NewDataListener(func() {}))
-			return
-		}
-		log.Println("START out:", string(out))
-		g.WaitDialog.HideWaitDialog()
-		refresh()
+			refresh()
+		}()
 	}
 	startButton.Disable()
+
+	// lifecycle actions wait for component health, so they run outside of ui callback
+	componentAction := func(name string, action func(ctx context.Context, c commandcontroller.Component) error) func(c commandcontroller.Component) {
+		return func(c commandcontroller.Component) {
+			go func() {
+				g.WaitDialog.ShowWaitDialog()
+				err := action(context.Background(), c)
+				g.WaitDialog.HideWaitDialog()
+				if err != nil {
+					log.Printf("ERROR when executing %v of %v: %v", name, c, err)
+					g.showErrorDialog(err, binding.NewDataListener(func() {}))
+				}
+				refresh()
+			}()
+		}
+	}
+	for _, c := range commandcontroller.Components {
+		row := newComponentRow(c,
+			componentAction("start", controller.Start),
+			componentAction("stop", controller.Stop),
+			componentAction("restart", controller.Restart),
+		)
+		componentRows[c] = row
+		componentsBox.Add(row.content)
+	}
+
 	refreshButton := widget.NewButton("Refresh", func() {
 		refresh()
 	})
@@ -215,6 +246,60 @@ func makeStatusScreen(_ fyne.Window, g *Gui) fyne.CanvasObject {
 			sekaiInfoBox,
 			shidaiInfoBox,
 			widget.NewSeparator(),
+			widget.NewLabel("Components"),
+			componentsBox,
+			widget.NewSeparator(),
 		))
 
 }
+
+// status and lifecycle buttons of a single sekin component
+type componentRow struct {
+	content       fyne.CanvasObject
+	stateLabel    *widget.Label
+	startButton   *widget.Button
+	stopButton    *widget.Button
+	restartButton *widget.Button
+}
+
+func newComponentRow(c commandcontroller.Component, start, stop, restart func(c commandcontroller.Component)) *componentRow {
+	r := &componentRow{
+		stateLabel:    widget.NewLabel(""),
+		startButton:   widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() { start(c) }),
+		stopButton:    widget.NewButtonWithIcon("", theme.MediaStopIcon(), func() { stop(c) }),
+		restartButton: widget.NewButtonWithIcon("", theme.MediaReplayIcon(), func() { restart(c) }),
+	}
+	r.stateLabel.Truncation = fyne.TextTruncateEllipsis
+	name := widget.NewLabel(fmt.Sprintf("%v:", c))
+	name.TextStyle.Bold = true
+	r.content = container.NewBorder(nil, nil, name, container.NewHBox(r.startButton, r.stopButton, r.restartButton), r.stateLabel)
+	r.update(commandcontroller.Status{Component: c, State: commandcontroller.Unknown})
+	return r
+}
+
+func (r *componentRow) update(s commandcontroller.Status) {
+	text := string(s.State)
+	r.stateLabel.Importance = widget.MediumImportance
+	switch {
+	case s.State == commandcontroller.Running && s.Healthy:
+		r.stateLabel.Importance = widget.SuccessImportance
+	case s.State == commandcontroller.Running:
+		text = fmt.Sprintf("running, not healthy: %v", s.Detail)
+		r.stateLabel.Importance = widget.WarningImportance
+	case s.State == commandcontroller.Unknown && s.Detail != "":
+		text = fmt.Sprintf("%v: %v", s.State, s.Detail)
+		r.stateLabel.Importance = widget.DangerImportance
+	}
+	r.stateLabel.SetText(text)
+
+	setEnabled := func(b *widget.Button, enabled bool) {
+		if enabled {
+			b.Enable()
+		} else {
+			b.Disable()
+		}
+	}
+	setEnabled(r.startButton, s.State == commandcontroller.Stopped || (s.State == commandcontroller.Running && !s.Healthy))
+	setEnabled(r.stopButton, s.State == commandcontroller.Running || s.State == commandcontroller.Unknown && s.Container != "")
+	setEnabled(r.restartButton, s.State == commandcontroller.Running || s.State == commandcontroller.Unknown && s.Container != "")
+}
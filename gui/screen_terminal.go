@@ -18,29 +18,29 @@ type Terminal struct {
 	SSHOut                io.Reader
 }
 
-func TryToRunSSHSessionForTerminal(g *Gui) (err error) {
-	g.Terminal.SSHSessionForTerminal, err = gssh.MakeSSHsessionForTerminalV2(g.sshClient)
+func TryToRunSSHSessionForTerminal(c *HostConnection) (err error) {
+	c.Terminal.SSHSessionForTerminal, err = gssh.MakeSSHsessionForTerminalV2(c.sshClient)
 	if err != nil {
 		return err
 	}
 	go func() {
-		err := g.Terminal.SSHSessionForTerminal.Shell()
+		err := c.Terminal.SSHSessionForTerminal.Shell()
 		if err != nil {
 			log.Println("Shell err: ", err.Error())
 		}
 	}()
 
-	g.Terminal.SSHIn, err = g.Terminal.SSHSessionForTerminal.StdinPipe()
+	c.Terminal.SSHIn, err = c.Terminal.SSHSessionForTerminal.StdinPipe()
 	if err != nil {
 		log.Println("SSHSessionForTerminal.StdinPipe", err)
 		return err
 	}
-	g.Terminal.SSHOut, err = g.Terminal.SSHSessionForTerminal.StdoutPipe()
+	c.Terminal.SSHOut, err = c.Terminal.SSHSessionForTerminal.StdoutPipe()
 	if err != nil {
 		log.Println("SSHSessionForTerminal.StdoutPipe", err)
 		return err
 	}
-	g.Terminal.Term = terminal.New()
+	c.Terminal.Term = terminal.New()
 
 	ch := make(chan terminal.Config)
 	go func() {
@@ -51,10 +51,10 @@ func TryToRunSSHSessionForTerminal(g *Gui) (err error) {
 				continue
 			}
 			rows, cols = config.Rows, config.Columns
-			g.Terminal.SSHSessionForTerminal.WindowChange(int(rows), int(cols))
+			c.Terminal.SSHSessionForTerminal.WindowChange(int(rows), int(cols))
 		}
 	}()
-	g.Terminal.Term.AddListener(ch)
+	c.Terminal.Term.AddListener(ch)
 
 	go func() {
 		defer func() {
@@ -62,7 +62,7 @@ func TryToRunSSHSessionForTerminal(g *Gui) (err error) {
 				log.Printf("Recovered in terminal: %v", r)
 			}
 		}()
-		err := g.Terminal.Term.RunWithConnection(g.Terminal.SSHIn, g.Terminal.SSHOut)
+		err := c.Terminal.Term.RunWithConnection(c.Terminal.SSHIn, c.Terminal.SSHOut)
 		if err != nil {
 			log.Println("RunWithConnection err: ", err.Error())
 		}
@@ -9,6 +9,11 @@ type Tab struct {
 
 var (
 	Tabs = map[string]Tab{
+		"fleet": {
+			Title: "Fleet overview",
+			Info:  "Dashboard of every connected host, select a row to open its Node Info",
+			View:  makeFleetOverviewScreen,
+		},
 		"status": {
 			Title: "Startup",
 			Info:  "",
@@ -19,6 +24,16 @@ var (
 			Info:  "",
 			View:  makeNodeInfoScreen,
 		},
+		"signing": {
+			Title: "Signing monitor",
+			Info:  "Blocks signed and missed by the validator of selected host, monitor keeps running in background",
+			View:  makeSigningScreen,
+		},
+		"alerts": {
+			Title: "Alerts",
+			Info:  "Alerts of all connected hosts, notifications are sent to desktop and configured webhooks",
+			View:  makeAlertsScreen,
+		},
 		"networkTree": {
 			Title: "Network visor",
 			Info:  "",
@@ -36,11 +51,21 @@ var (
 			Title: "Configs",
 			View:  makeCfgEditorScreen,
 		},
+		"forwards": {
+			Title: "Port forwarding",
+			Info:  "Forwards are kept per host and restarted after reconnect",
+			View:  makeForwardsScreen,
+		},
+		"files": {
+			Title: "Files",
+			Info:  "Browse remote host files, select a file to download, rename, change permissions or delete it",
+			View:  makeFilesScreen,
+		},
 		"test": {},
 	}
 
 	TabsIndex = map[string][]string{
-		"":     {"status", "nodeInfo", "networkTree", "config", "terminal", "logs"},
+		"":     {"fleet", "status", "nodeInfo", "signing", "alerts", "networkTree", "config", "files", "forwards", "terminal", "logs"},
 		"test": {"a", "b"},
 	}
 )
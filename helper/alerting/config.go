@@ -0,0 +1,128 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+const DEFAULT_MISCHANCE_THRESHOLD int = 10
+
+// Silence suppresses delivery of matching alerts until Until, empty Host or Rule matches any
+type Silence struct {
+	Host  string    `json:"host,omitempty"`
+	Rule  string    `json:"rule,omitempty"`
+	Until time.Time `json:"until"`
+}
+
+func (s Silence) Matches(host, rule string, now time.Time) bool {
+	return now.Before(s.Until) && (s.Host == "" || s.Host == host) && (s.Rule == "" || s.Rule == rule)
+}
+
+type Config struct {
+	Webhooks []Webhook `json:"webhooks"`
+	// mischance value at which mischance rule fires
+	MischanceThreshold int       `json:"mischance_threshold"`
+	DisabledRules      []string  `json:"disabled_rules,omitempty"`
+	Silences           []Silence `json:"silences,omitempty"`
+}
+
+func DefaultConfig() Config {
+	return Config{MischanceThreshold: DEFAULT_MISCHANCE_THRESHOLD}
+}
+
+func (c Config) RuleEnabled(name string) bool {
+	return !slices.Contains(c.DisabledRules, name)
+}
+
+func (c Config) Silenced(host, rule string, now time.Time) bool {
+	for _, s := range c.Silences {
+		if s.Matches(host, rule, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// copy which does not share slices with c
+func (c Config) clone() Config {
+	c.Webhooks = slices.Clone(c.Webhooks)
+	c.DisabledRules = slices.Clone(c.DisabledRules)
+	c.Silences = slices.Clone(c.Silences)
+	return c
+}
+
+// drops expired silences
+func (c *Config) pruneSilences(now time.Time) {
+	c.Silences = slices.DeleteFunc(c.Silences, func(s Silence) bool { return !now.Before(s.Until) })
+}
+
+func (c Config) Validate() error {
+	if c.MischanceThreshold < 0 {
+		return fmt.Errorf("mischance threshold cannot be negative")
+	}
+	for _, w := range c.Webhooks {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigStore keeps alerting config in json file
+type ConfigStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewConfigStore(path string) *ConfigStore {
+	return &ConfigStore{path: path}
+}
+
+// returns saved config, missing file means default config
+func (s *ConfigStore) Load() (Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultConfig(), nil
+		}
+		return DefaultConfig(), fmt.Errorf("unable to read alerting config: %w", err)
+	}
+	cfg := DefaultConfig()
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return DefaultConfig(), fmt.Errorf("unable to parse alerting config <%v>: %w", s.path, err)
+	}
+	return cfg, nil
+}
+
+func (s *ConfigStore) Save(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	// webhook urls usually contain tokens
+	if err = os.WriteFile(s.path, b, 0600); err != nil {
+		return fmt.Errorf("unable to write alerting config: %w", err)
+	}
+	return nil
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("<%v> is not valid http(s) url", raw)
+	}
+	return nil
+}
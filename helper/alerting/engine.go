@@ -0,0 +1,293 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	historySize     = 200
+	deliveryTimeout = 10 * time.Second
+)
+
+type Alert struct {
+	Host     string    `json:"host"`
+	Rule     string    `json:"rule"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+	Since    time.Time `json:"since"`
+}
+
+// sent when alert starts firing or gets resolved
+type Notification struct {
+	Alert
+	Resolved bool      `json:"resolved"`
+	Time     time.Time `json:"time"`
+	// silenced notifications are kept in history but not delivered
+	Silenced bool `json:"-"`
+}
+
+func (n Notification) String() string {
+	if n.Resolved {
+		return fmt.Sprintf("[RESOLVED] %v: %v", n.Host, n.Message)
+	}
+	return fmt.Sprintf("[%v] %v: %v", n.Severity, n.Host, n.Message)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type NotifierFunc func(ctx context.Context, n Notification) error
+
+func (f NotifierFunc) Notify(ctx context.Context, n Notification) error {
+	return f(ctx, n)
+}
+
+// Engine evaluates rules against host snapshots and notifies only on state changes
+type Engine struct {
+	store     *ConfigStore
+	rules     []Rule
+	notifiers []Notifier
+
+	mu      sync.Mutex
+	cfg     Config
+	active  map[string]Alert
+	prev    map[string]Snapshot
+	history []Notification
+	// replaced in tests
+	now func() time.Time
+}
+
+// notifiers are always used, webhooks from config are added on delivery
+func NewEngine(store *ConfigStore, rules []Rule, notifiers ...Notifier) *Engine {
+	cfg, err := store.Load()
+	if err != nil {
+		log.Printf("Using default alerting config: %v", err)
+	}
+	return &Engine{
+		store:     store,
+		rules:     rules,
+		notifiers: notifiers,
+		cfg:       cfg,
+		active:    map[string]Alert{},
+		prev:      map[string]Snapshot{},
+		now:       time.Now,
+	}
+}
+
+func alertKey(host, rule string) string {
+	return host + "/" + rule
+}
+
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+func (e *Engine) Config() Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cfg.clone()
+}
+
+// saves config, alerts of disabled rules are resolved
+func (e *Engine) SetConfig(cfg Config) error {
+	e.mu.Lock()
+	resolved, err := e.setConfig(cfg)
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	e.deliver(resolved)
+	return nil
+}
+
+// must be called with e.mu held, returns notifications of resolved alerts to deliver
+func (e *Engine) setConfig(cfg Config) ([]Notification, error) {
+	// pruning edits slices in place, so the caller's config is not shared
+	cfg = cfg.clone()
+	cfg.pruneSilences(e.now())
+	if err := e.store.Save(cfg); err != nil {
+		return nil, err
+	}
+	e.cfg = cfg
+	var resolved []Notification
+	for key, a := range e.active {
+		if !cfg.RuleEnabled(a.Rule) {
+			delete(e.active, key)
+			resolved = append(resolved, e.record(a, true))
+		}
+	}
+	return resolved, nil
+}
+
+// Evaluate runs all enabled rules for the snapshot host
+func (e *Engine) Evaluate(s Snapshot) {
+	if s.Time.IsZero() {
+		s.Time = e.now()
+	}
+	e.mu.Lock()
+	prev := e.prev[s.Host]
+	next := s
+	// streak and similar rules compare against last known dashboard
+	if next.Dashboard == nil {
+		next.Dashboard = prev.Dashboard
+	}
+	e.prev[s.Host] = next
+
+	var out []Notification
+	for _, r := range e.rules {
+		if !e.cfg.RuleEnabled(r.Name) {
+			continue
+		}
+		firing, msg, ok := r.Check(e.cfg, prev, s)
+		if !ok {
+			continue
+		}
+		if n, changed := e.transition(s.Host, r.Name, r.Severity, msg, firing, s.Time); changed {
+			out = append(out, n)
+		}
+	}
+	e.mu.Unlock()
+	e.deliver(out)
+}
+
+// Fire raises alert computed outside of rules, e.g. by signing monitor
+func (e *Engine) Fire(host, rule string, severity Severity, message string) {
+	e.set(host, rule, severity, message, true)
+}
+
+func (e *Engine) Resolve(host, rule, message string) {
+	e.set(host, rule, "", message, false)
+}
+
+func (e *Engine) set(host, rule string, severity Severity, message string, firing bool) {
+	e.mu.Lock()
+	if !e.cfg.RuleEnabled(rule) {
+		e.mu.Unlock()
+		return
+	}
+	n, changed := e.transition(host, rule, severity, message, firing, e.now())
+	e.mu.Unlock()
+	if changed {
+		e.deliver([]Notification{n})
+	}
+}
+
+// must be called with e.mu held
+func (e *Engine) transition(host, rule string, severity Severity, msg string, firing bool, now time.Time) (Notification, bool) {
+	key := alertKey(host, rule)
+	a, active := e.active[key]
+	switch {
+	case firing && !active:
+		a = Alert{Host: host, Rule: rule, Severity: severity, Message: msg, Since: now}
+		e.active[key] = a
+		return e.record(a, false), true
+	case !firing && active:
+		delete(e.active, key)
+		if msg != "" {
+			a.Message = msg
+		}
+		return e.record(a, true), true
+	case firing && active:
+		// keep message current without notifying again
+		a.Message = msg
+		e.active[key] = a
+	}
+	return Notification{}, false
+}
+
+// must be called with e.mu held
+func (e *Engine) record(a Alert, resolved bool) Notification {
+	now := e.now()
+	n := Notification{Alert: a, Resolved: resolved, Time: now, Silenced: e.cfg.Silenced(a.Host, a.Rule, now)}
+	e.history = append(e.history, n)
+	if len(e.history) > historySize {
+		e.history = e.history[len(e.history)-historySize:]
+	}
+	return n
+}
+
+func (e *Engine) deliver(ns []Notification) {
+	if len(ns) == 0 {
+		return
+	}
+	e.mu.Lock()
+	notifiers := append([]Notifier(nil), e.notifiers...)
+	for _, w := range e.cfg.Webhooks {
+		notifiers = append(notifiers, w)
+	}
+	e.mu.Unlock()
+
+	for _, n := range ns {
+		log.Printf("Alert: %v", n)
+		if n.Silenced {
+			continue
+		}
+		for _, notifier := range notifiers {
+			go func(notifier Notifier, n Notification) {
+				ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+				defer cancel()
+				if err := notifier.Notify(ctx, n); err != nil {
+					log.Printf("Unable to deliver alert notification: %v", err)
+				}
+			}(notifier, n)
+		}
+	}
+}
+
+// returns firing alerts sorted by host and rule
+func (e *Engine) Active() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Alert, 0, len(e.active))
+	for _, a := range e.active {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return alertKey(out[i].Host, out[i].Rule) < alertKey(out[j].Host, out[j].Rule)
+	})
+	return out
+}
+
+// returns notifications, newest first
+func (e *Engine) History() []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Notification, len(e.history))
+	for i, n := range e.history {
+		out[len(out)-1-i] = n
+	}
+	return out
+}
+
+// Silence stops delivery for host and rule for duration d, empty host or rule matches any
+func (e *Engine) Silence(host, rule string, d time.Duration) error {
+	// read-modify-write under one lock, so concurrent silences are not lost
+	e.mu.Lock()
+	cfg := e.cfg.clone()
+	cfg.Silences = append(cfg.Silences, Silence{Host: host, Rule: rule, Until: e.now().Add(d)})
+	resolved, err := e.setConfig(cfg)
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	e.deliver(resolved)
+	return nil
+}
+
+// Forget drops all state of host without notifying, used when host is removed from the fleet
+func (e *Engine) Forget(host string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.prev, host)
+	for key, a := range e.active {
+		if a.Host == host {
+			delete(e.active, key)
+		}
+	}
+}
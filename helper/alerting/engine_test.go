@@ -0,0 +1,221 @@
+package alerting
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+var testStart = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// notifier which records delivered notifications
+type fakeNotifier struct {
+	mu        sync.Mutex
+	delivered []Notification
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, n Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delivered = append(f.delivered, n)
+	return nil
+}
+
+// waits until n notifications are delivered, delivery runs in background
+func (f *fakeNotifier) wait(t *testing.T, n int) []Notification {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		f.mu.Lock()
+		got := append([]Notification(nil), f.delivered...)
+		f.mu.Unlock()
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered %v notifications, want %v", len(got), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// engine with default rules, fake clock starting at testStart and config in temp dir
+func newTestEngine(t *testing.T) (*Engine, *fakeNotifier, *time.Time) {
+	t.Helper()
+	notifier := &fakeNotifier{}
+	e := NewEngine(NewConfigStore(filepath.Join(t.TempDir(), "alerting.json")), DefaultRules(), notifier)
+	now := testStart
+	e.now = func() time.Time { return now }
+	return e, notifier, &now
+}
+
+type transition struct {
+	Rule     string
+	Resolved bool
+}
+
+// history oldest first, without timestamps
+func transitions(e *Engine) []transition {
+	var out []transition
+	history := e.History()
+	for i := len(history) - 1; i >= 0; i-- {
+		out = append(out, transition{history[i].Rule, history[i].Resolved})
+	}
+	return out
+}
+
+func TestEngineEvaluateTransitions(t *testing.T) {
+	streak := func(s string) *shidai.Dashboard { return &shidai.Dashboard{Streak: s} }
+	tests := []struct {
+		name      string
+		snapshots []Snapshot
+		want      []transition
+	}{
+		{
+			name:      "healthy host does not notify",
+			snapshots: []Snapshot{{Connected: true, Dashboard: streak("1")}, {Connected: true, Dashboard: streak("2")}},
+		},
+		{
+			name: "firing alert is notified once and resolved",
+			snapshots: []Snapshot{
+				{Connected: false},
+				{Connected: false},
+				{Connected: false},
+				{Connected: true, Dashboard: streak("1")},
+			},
+			want: []transition{{RuleConnectionLost, false}, {RuleConnectionLost, true}},
+		},
+		{
+			name: "rule which cannot be evaluated keeps state",
+			snapshots: []Snapshot{
+				{Connected: true, Err: context.DeadlineExceeded},
+				// shidai rule is not evaluated while disconnected
+				{Connected: false},
+				{Connected: true, Err: context.DeadlineExceeded},
+				{Connected: true, Dashboard: streak("1")},
+			},
+			want: []transition{
+				{RuleShidaiUnreachable, false},
+				{RuleConnectionLost, false},
+				{RuleConnectionLost, true},
+				{RuleShidaiUnreachable, true},
+			},
+		},
+		{
+			name: "streak drop is compared with last known dashboard",
+			snapshots: []Snapshot{
+				{Connected: true, Dashboard: streak("10")},
+				{Connected: true, Err: context.DeadlineExceeded},
+				{Connected: true, Dashboard: streak("3")},
+				{Connected: true, Dashboard: streak("4")},
+			},
+			want: []transition{
+				{RuleShidaiUnreachable, false},
+				// rules are evaluated in order within one snapshot
+				{RuleShidaiUnreachable, true},
+				{RuleStreakReset, false},
+				{RuleStreakReset, true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, notifier, now := newTestEngine(t)
+			for _, s := range tt.snapshots {
+				s.Host = "node"
+				s.Time = *now
+				e.Evaluate(s)
+				*now = now.Add(time.Minute)
+			}
+			if got := transitions(e); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("transitions %v, want %v", got, tt.want)
+			}
+			notifier.wait(t, len(tt.want))
+			if len(e.Active()) != 0 {
+				t.Fatalf("alerts left active: %v", e.Active())
+			}
+		})
+	}
+}
+
+func TestEngineSilenceExpiry(t *testing.T) {
+	e, notifier, now := newTestEngine(t)
+	if err := e.Silence("node", RuleConnectionLost, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// silenced alert is kept in history, but only the other host is delivered
+	e.Evaluate(Snapshot{Host: "node", Time: *now})
+	e.Evaluate(Snapshot{Host: "other", Time: *now})
+	if h := e.History(); len(h) != 2 || !h[1].Silenced || h[0].Silenced {
+		t.Fatalf("unexpected history %+v", h)
+	}
+	if got := notifier.wait(t, 1); got[0].Host != "other" {
+		t.Fatalf("silenced alert was delivered: %+v", got)
+	}
+
+	// silence expires, resolve of the same alert is delivered
+	*now = now.Add(time.Hour)
+	e.Evaluate(Snapshot{Host: "node", Connected: true, Time: *now})
+	if got := notifier.wait(t, 2); got[1].Host != "node" || !got[1].Resolved || got[1].Silenced {
+		t.Fatalf("unexpected delivery after silence expired: %+v", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := notifier.wait(t, 2); len(got) != 2 {
+		t.Fatalf("unexpected deliveries %+v", got)
+	}
+
+	// expired silence is dropped on next config change
+	if err := e.SetConfig(e.Config()); err != nil {
+		t.Fatal(err)
+	}
+	if s := e.Config().Silences; len(s) != 0 {
+		t.Fatalf("expired silences were kept: %v", s)
+	}
+}
+
+func TestEngineDisabledRuleResolves(t *testing.T) {
+	e, notifier, now := newTestEngine(t)
+	e.Evaluate(Snapshot{Host: "node", Time: *now})
+
+	cfg := e.Config()
+	cfg.DisabledRules = []string{RuleConnectionLost}
+	if err := e.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []transition{{RuleConnectionLost, false}, {RuleConnectionLost, true}}
+	if got := transitions(e); !reflect.DeepEqual(got, want) {
+		t.Fatalf("transitions %v, want %v", got, want)
+	}
+	notifier.wait(t, 2)
+
+	// disabled rule is not evaluated anymore
+	e.Evaluate(Snapshot{Host: "node", Time: *now})
+	e.Fire("node", RuleConnectionLost, Critical, "lost")
+	if len(e.Active()) != 0 {
+		t.Fatalf("disabled rule fired: %v", e.Active())
+	}
+}
+
+func TestEngineConcurrentSilence(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.Silence("node", "", time.Hour); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if s := e.Config().Silences; len(s) != 10 {
+		t.Fatalf("kept %v of 10 silences", len(s))
+	}
+}
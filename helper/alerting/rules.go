@@ -0,0 +1,145 @@
+package alerting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+type Severity string
+
+const (
+	Warning  Severity = "warning"
+	Critical Severity = "critical"
+)
+
+// state of a single host at evaluation time
+type Snapshot struct {
+	Host      string
+	Connected bool
+	// nil when dashboard could not be fetched, Err has the reason
+	Dashboard *shidai.Dashboard
+	Err       error
+	Time      time.Time
+}
+
+// Rule decides whether alert should be firing for the host.
+// When ok is false rule cannot be evaluated from the snapshot and alert keeps its previous state.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(cfg Config, prev, cur Snapshot) (firing bool, message string, ok bool)
+}
+
+const (
+	RuleConnectionLost    = "connection_lost"
+	RuleShidaiUnreachable = "shidai_unreachable"
+	RuleValidatorJailed   = "validator_jailed"
+	RuleValidatorInactive = "validator_inactive"
+	RuleCatchingUp        = "catching_up"
+	RuleMischanceHigh     = "mischance_high"
+	RuleStreakReset       = "streak_reset"
+	// raised from signing monitor, not evaluated from snapshots
+	RuleSigningMissRate = "signing_miss_rate"
+)
+
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:        RuleConnectionLost,
+			Description: "SSH connection to the host is lost",
+			Severity:    Critical,
+			Check: func(_ Config, _, cur Snapshot) (bool, string, bool) {
+				return !cur.Connected, "ssh connection lost", true
+			},
+		},
+		{
+			Name:        RuleShidaiUnreachable,
+			Description: "Shidai API does not respond",
+			Severity:    Warning,
+			Check: func(_ Config, _, cur Snapshot) (bool, string, bool) {
+				if !cur.Connected {
+					return false, "", false
+				}
+				if cur.Err != nil {
+					return true, fmt.Sprintf("shidai unreachable: %v", cur.Err), true
+				}
+				return false, "", true
+			},
+		},
+		{
+			Name:        RuleValidatorJailed,
+			Description: "Validator status is JAILED",
+			Severity:    Critical,
+			Check: dashboardCheck(func(_ Config, _ *shidai.Dashboard, d *shidai.Dashboard) (bool, string) {
+				return validatorStatus(d) == "JAILED", "validator is jailed"
+			}),
+		},
+		{
+			Name:        RuleValidatorInactive,
+			Description: "Validator status is INACTIVE or PAUSED",
+			Severity:    Warning,
+			Check: dashboardCheck(func(_ Config, _ *shidai.Dashboard, d *shidai.Dashboard) (bool, string) {
+				status := validatorStatus(d)
+				return status == "INACTIVE" || status == "PAUSED", fmt.Sprintf("validator is %v", strings.ToLower(status))
+			}),
+		},
+		{
+			Name:        RuleCatchingUp,
+			Description: "Node is catching up with the network",
+			Severity:    Warning,
+			Check: dashboardCheck(func(_ Config, _ *shidai.Dashboard, d *shidai.Dashboard) (bool, string) {
+				return d.CatchingUp, "node is catching up"
+			}),
+		},
+		{
+			Name:        RuleMischanceHigh,
+			Description: "Validator mischance reached the threshold",
+			Severity:    Warning,
+			Check: dashboardCheck(func(cfg Config, _ *shidai.Dashboard, d *shidai.Dashboard) (bool, string) {
+				mischance, err := strconv.Atoi(d.Mischance)
+				if err != nil {
+					return false, ""
+				}
+				return cfg.MischanceThreshold > 0 && mischance >= cfg.MischanceThreshold,
+					fmt.Sprintf("mischance is %v (threshold %v)", mischance, cfg.MischanceThreshold)
+			}),
+		},
+		{
+			Name:        RuleStreakReset,
+			Description: "Block production streak dropped",
+			Severity:    Warning,
+			// fires once per drop, resolves on next evaluation when streak grows again
+			Check: dashboardCheck(func(_ Config, prev *shidai.Dashboard, d *shidai.Dashboard) (bool, string) {
+				if prev == nil {
+					return false, ""
+				}
+				before, err1 := strconv.Atoi(prev.Streak)
+				now, err2 := strconv.Atoi(d.Streak)
+				if err1 != nil || err2 != nil {
+					return false, ""
+				}
+				return now < before, fmt.Sprintf("streak dropped from %v to %v", before, now)
+			}),
+		},
+	}
+}
+
+// wraps check which needs dashboard, snapshots without dashboard keep previous state
+func dashboardCheck(check func(cfg Config, prev, cur *shidai.Dashboard) (bool, string)) func(Config, Snapshot, Snapshot) (bool, string, bool) {
+	return func(cfg Config, prev, cur Snapshot) (bool, string, bool) {
+		if cur.Dashboard == nil {
+			return false, "", false
+		}
+		firing, msg := check(cfg, prev.Dashboard, cur.Dashboard)
+		return firing, msg, true
+	}
+}
+
+func validatorStatus(d *shidai.Dashboard) string {
+	return strings.ToUpper(strings.TrimSpace(d.ValidatorStatus))
+}
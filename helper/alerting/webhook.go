@@ -0,0 +1,70 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type WebhookFormat string
+
+const (
+	SlackWebhook   WebhookFormat = "slack"
+	DiscordWebhook WebhookFormat = "discord"
+	// Notification posted as is
+	JSONWebhook WebhookFormat = "json"
+)
+
+var WebhookFormats = []WebhookFormat{SlackWebhook, DiscordWebhook, JSONWebhook}
+
+type Webhook struct {
+	Name   string        `json:"name"`
+	URL    string        `json:"url"`
+	Format WebhookFormat `json:"format"`
+}
+
+func (w Webhook) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("webhook name cannot be empty")
+	}
+	switch w.Format {
+	case SlackWebhook, DiscordWebhook, JSONWebhook:
+	default:
+		return fmt.Errorf("unknown webhook format <%v>", w.Format)
+	}
+	return validateURL(w.URL)
+}
+
+func (w Webhook) Notify(ctx context.Context, n Notification) error {
+	var payload any
+	switch w.Format {
+	case SlackWebhook:
+		payload = map[string]string{"text": n.String()}
+	case DiscordWebhook:
+		payload = map[string]string{"content": n.String()}
+	default:
+		payload = n
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook <%v> failed: %w", w.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook <%v> failed with status %d: %s", w.Name, resp.StatusCode, string(body))
+	}
+	return nil
+}
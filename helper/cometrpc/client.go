@@ -0,0 +1,171 @@
+package cometrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	sekaiendpoint "github.com/KiraCore/kensho/types/endpoint/sekai"
+)
+
+const (
+	// deadline of requests when ctx has none
+	DefaultTimeout = 10 * time.Second
+	// bodies bigger than this are rejected instead of being read into memory
+	maxResponseSize = 32 << 20
+)
+
+var ErrResponseTooLarge = errors.New("response body exceeds size limit")
+
+// error object of json-rpc response
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %v %v", e.Code, e.Message, e.Data)
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// opens tcp connection for websocket, e.g. ssh client DialContext to reach RPC bound to localhost of the node
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Client talks to CometBFT RPC of sekai, over plain HTTP and over /websocket for event subscriptions
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	dial       DialFunc
+}
+
+// baseURL is e.g. "http://1.2.3.4:26657", nil httpClient means http.DefaultClient, nil dial means direct tcp
+func New(baseURL string, httpClient *http.Client, dial DialFunc) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient, dial: dial}
+}
+
+func NewForNode(ip string, port int) *Client {
+	return New(fmt.Sprintf("http://%v:%v", ip, port), nil, nil)
+}
+
+func (c *Client) Status(ctx context.Context) (*sekaiendpoint.Status, error) {
+	var data sekaiendpoint.Status
+	if err := c.call(ctx, "status", nil, &data.Result); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) ABCIInfo(ctx context.Context) (*sekaiendpoint.ABCI_Info, error) {
+	var data sekaiendpoint.ABCI_Info
+	if err := c.call(ctx, "abci_info", nil, &data.ABCI_result); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// returns block at height, height 0 means latest block
+func (c *Client) Block(ctx context.Context, height int64) (*sekaiendpoint.BlockResult, error) {
+	var data sekaiendpoint.BlockResult
+	if err := c.call(ctx, "block", heightParams(height), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// returns commit with signatures of block at height, height 0 means latest commit
+func (c *Client) Commit(ctx context.Context, height int64) (*sekaiendpoint.CommitResult, error) {
+	var data sekaiendpoint.CommitResult
+	if err := c.call(ctx, "commit", heightParams(height), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// returns one page of validator set at height, height 0 means latest
+func (c *Client) Validators(ctx context.Context, height int64, page, perPage int) (*sekaiendpoint.ValidatorsResult, error) {
+	params := heightParams(height)
+	if page > 0 {
+		params.Set("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		params.Set("per_page", strconv.Itoa(perPage))
+	}
+	var data sekaiendpoint.ValidatorsResult
+	if err := c.call(ctx, "validators", params, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// calls RPC method with URI params and decodes result into data
+func (c *Client) call(ctx context.Context, method string, params url.Values, data any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	address := c.baseURL + "/" + method
+	if len(params) > 0 {
+		address += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return fmt.Errorf("unable to read <%v> response: %w", method, err)
+	}
+	if len(b) > maxResponseSize {
+		return fmt.Errorf("<%v>: %w", method, ErrResponseTooLarge)
+	}
+
+	var r rpcResponse
+	if err = json.Unmarshal(b, &r); err != nil {
+		return fmt.Errorf("unable to decode <%v> response (status %v): %w", method, resp.StatusCode, err)
+	}
+	if r.Error != nil {
+		return r.Error
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("<%v> failed with status %v", method, resp.StatusCode)
+	}
+	if err = json.Unmarshal(r.Result, data); err != nil {
+		return fmt.Errorf("unable to decode <%v> result: %w", method, err)
+	}
+	return nil
+}
+
+func heightParams(height int64) url.Values {
+	params := url.Values{}
+	if height > 0 {
+		params.Set("height", strconv.FormatInt(height, 10))
+	}
+	return params
+}
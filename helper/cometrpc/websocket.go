@@ -0,0 +1,194 @@
+package cometrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	sekaiendpoint "github.com/KiraCore/kensho/types/endpoint/sekai"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	NewBlockQuery = "tm.event='NewBlock'"
+	TxQuery       = "tm.event='Tx'"
+
+	// connection without any message for this long is treated as dead and reconnected,
+	// NewBlock subscription produces message every few seconds
+	wsReadTimeout  = 2 * time.Minute
+	wsWriteTimeout = 10 * time.Second
+
+	resubscribeInitialDelay = time.Second
+	resubscribeMaxDelay     = 30 * time.Second
+)
+
+// single event of a subscription
+type Event struct {
+	Query string
+	// e.g. "tendermint/event/NewBlock"
+	Type  string
+	Value json.RawMessage
+	// indexed event attributes, e.g. "tx.hash"
+	Attributes map[string][]string
+}
+
+func (e Event) NewBlock() (*sekaiendpoint.EventNewBlock, error) {
+	var data sekaiendpoint.EventNewBlock
+	if err := json.Unmarshal(e.Value, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode NewBlock event: %w", err)
+	}
+	return &data, nil
+}
+
+func (e Event) Tx() (*sekaiendpoint.EventTx, error) {
+	var data sekaiendpoint.EventTx
+	if err := json.Unmarshal(e.Value, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode Tx event: %w", err)
+	}
+	return &data, nil
+}
+
+type wsRequest struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      int            `json:"id"`
+	Method  string         `json:"method"`
+	Params  map[string]any `json:"params"`
+}
+
+type wsResponse struct {
+	ID     int `json:"id"`
+	Result struct {
+		Query string `json:"query"`
+		Data  struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"data"`
+		Events map[string][]string `json:"events"`
+	} `json:"result"`
+	Error *RPCError `json:"error"`
+}
+
+// Subscribe streams events of queries from /websocket until ctx is cancelled, then the channel is closed.
+// Lost connection is reopened with backoff and all queries are subscribed again, events sent while
+// disconnected are not replayed. onStatus (may be nil) is called with nil after every successful
+// subscribe and with the error whenever connection is lost
+func (c *Client) Subscribe(ctx context.Context, queries []string, onStatus func(err error)) <-chan Event {
+	events := make(chan Event, 64)
+	if onStatus == nil {
+		onStatus = func(error) {}
+	}
+	go func() {
+		defer close(events)
+		delay := resubscribeInitialDelay
+		for {
+			subscribed, err := c.subscribeOnce(ctx, queries, events, onStatus)
+			if ctx.Err() != nil {
+				return
+			}
+			if subscribed {
+				delay = resubscribeInitialDelay
+			}
+			log.Printf("RPC websocket subscription lost, reconnecting in %v: %v", delay, err)
+			onStatus(err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, resubscribeMaxDelay)
+		}
+	}()
+	return events
+}
+
+// runs single websocket connection, subscribed reports whether all queries were accepted
+func (c *Client) subscribeOnce(ctx context.Context, queries []string, events chan<- Event, onStatus func(error)) (subscribed bool, err error) {
+	ws, err := c.dialWebsocket(ctx)
+	if err != nil {
+		return false, err
+	}
+	// unblocks Receive when ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { ws.Close() })
+	defer stop()
+	defer ws.Close()
+
+	for i, q := range queries {
+		ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		err = websocket.JSON.Send(ws, wsRequest{JSONRPC: "2.0", ID: i, Method: "subscribe", Params: map[string]any{"query": q}})
+		if err != nil {
+			return false, fmt.Errorf("unable to subscribe to <%v>: %w", q, err)
+		}
+	}
+
+	pending := len(queries)
+	for {
+		ws.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		var msg wsResponse
+		if err = websocket.JSON.Receive(ws, &msg); err != nil {
+			return subscribed, err
+		}
+		if msg.Error != nil {
+			return subscribed, msg.Error
+		}
+		// empty result confirms subscribe request
+		if msg.Result.Query == "" {
+			if pending--; pending == 0 && !subscribed {
+				subscribed = true
+				log.Printf("Subscribed to %v on <%v>", queries, c.baseURL)
+				onStatus(nil)
+			}
+			continue
+		}
+		select {
+		case events <- Event{Query: msg.Result.Query, Type: msg.Result.Data.Type, Value: msg.Result.Data.Value, Attributes: msg.Result.Events}:
+		case <-ctx.Done():
+			return subscribed, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) dialWebsocket(ctx context.Context) (*websocket.Conn, error) {
+	u, err := url.Parse(c.baseURL + "/websocket")
+	if err != nil {
+		return nil, err
+	}
+	origin := *u
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	config, err := websocket.NewConfig(u.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		port := 80
+		if u.Scheme == "wss" {
+			port = 443
+		}
+		addr = u.Hostname() + ":" + strconv.Itoa(port)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	conn, err := c.dial(dialCtx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to <%v>: %w", addr, err)
+	}
+	if u.Scheme == "wss" {
+		conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake with <%v> failed: %w", u, err)
+	}
+	return ws, nil
+}
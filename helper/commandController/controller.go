@@ -1,17 +1,339 @@
 package commandcontroller
 
-func StartInterx() error {
-	return nil
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"golang.org/x/crypto/ssh"
+)
+
+// Component is a sekin service, each of them runs in its own docker compose container
+type Component string
+
+const (
+	Sekai  Component = "sekai"
+	Interx Component = "interx"
+	Shidai Component = "shidai"
+	Syslog Component = "syslog-ng"
+)
+
+var Components = []Component{Sekai, Interx, Shidai, Syslog}
+
+// interx reads chain data from sekai, so it is started after and stopped before sekai
+var dependencies = map[Component][]Component{
+	Interx: {Sekai},
+}
+
+type State string
+
+const (
+	Running State = "running"
+	Stopped State = "stopped"
+	// container of the component does not exist, sekin is not deployed
+	Missing State = "missing"
+	// other docker states, e.g. restarting or paused
+	Unknown State = "unknown"
+)
+
+const (
+	DefaultHealthTimeout = 2 * time.Minute
+	DefaultPollInterval  = 3 * time.Second
+)
+
+var (
+	ErrPrecondition = errors.New("precondition failed")
+	ErrNotHealthy   = errors.New("component did not become healthy")
+	// docker needs sudo, but no password is known and sudo asks for one
+	ErrSudoPasswordRequired = errors.New("sudo password required")
+)
+
+type Status struct {
+	Component Component
+	Container string
+	State     State
+	// result of health check, only checked for running components with health check
+	Healthy bool
+	// docker state or health check error
+	Detail string
+}
+
+// Runner runs shell command on the node and returns its stdout
+type Runner func(ctx context.Context, command string) (string, error)
+
+// HealthCheck returns nil when component answers on its API
+type HealthCheck func(ctx context.Context) error
+
+// SSHRunner runs commands directly when user can talk to docker (root or docker group), otherwise with sudo.
+// Access is probed on the first command; without password and NOPASSWD sudo every command returns
+// ErrSudoPasswordRequired, so sudo is never answered with an empty password
+func SSHRunner(client *ssh.Client, sudoPassword string) Runner {
+	r := &sshRunner{client: client, sudoPassword: sudoPassword}
+	return r.run
+}
+
+type sudoMode int
+
+const (
+	sudoUnknown sudoMode = iota
+	// docker works without sudo
+	sudoNone
+	sudoWithPassword
+	sudoNoPassword
+	sudoUnavailable
+)
+
+type sshRunner struct {
+	client       *ssh.Client
+	sudoPassword string
+
+	mu   sync.Mutex
+	mode sudoMode
+}
+
+func (r *sshRunner) run(ctx context.Context, command string) (string, error) {
+	mode, err := r.probe(ctx)
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	switch mode {
+	case sudoNone:
+		_, err = gssh.Exec(ctx, r.client, command, &stdout, &stderr)
+	case sudoNoPassword:
+		_, err = gssh.Exec(ctx, r.client, "sudo -n -- sh -c "+gssh.ShellQuote(command), &stdout, &stderr)
+	case sudoWithPassword:
+		_, err = gssh.ExecSudo(ctx, r.client, command, r.sudoPassword, &stdout, &stderr)
+	default:
+		return "", ErrSudoPasswordRequired
+	}
+	if err != nil {
+		return stdout.String(), fmt.Errorf("%w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// result is kept once known, connection errors are returned and probed again next time
+func (r *sshRunner) probe(ctx context.Context) (sudoMode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != sudoUnknown {
+		return r.mode, nil
+	}
+
+	if r.client.User() == "root" {
+		r.mode = sudoNone
+		return r.mode, nil
+	}
+	result, err := gssh.Exec(ctx, r.client, "docker info --format '{{.ID}}'", nil, nil)
+	switch {
+	case err == nil:
+		r.mode = sudoNone
+		return r.mode, nil
+	case result.ExitCode < 0:
+		return sudoUnknown, fmt.Errorf("unable to check docker access: %w", err)
+	case r.sudoPassword != "":
+		r.mode = sudoWithPassword
+		return r.mode, nil
+	}
+	noPassword, err := gssh.SudoNoPasswordRequired(ctx, r.client)
+	if err != nil {
+		return sudoUnknown, err
+	}
+	if !noPassword {
+		log.Printf("User <%v> needs sudo password to run docker, none was given", r.client.User())
+		r.mode = sudoUnavailable
+		return r.mode, nil
+	}
+	r.mode = sudoNoPassword
+	return r.mode, nil
 }
 
-func StartSekai() error {
-	return nil
+// Controller starts and stops sekin components one by one
+type Controller struct {
+	run    Runner
+	health map[Component]HealthCheck
+
+	HealthTimeout time.Duration
+	PollInterval  time.Duration
 }
 
-func StopInterx() error {
-	return nil
+// components without health check are considered healthy once their container runs
+func New(run Runner, health map[Component]HealthCheck) *Controller {
+	return &Controller{run: run, health: health, HealthTimeout: DefaultHealthTimeout, PollInterval: DefaultPollInterval}
 }
 
-func StopSekai() error {
-	return nil
+// container name of compose service, empty when container does not exist
+func (c *Controller) container(ctx context.Context, component Component) (string, error) {
+	out, err := c.run(ctx, fmt.Sprintf("docker ps -a --filter %v --format '{{.Names}}'",
+		gssh.ShellQuote("label=com.docker.compose.service="+string(component))))
+	if err != nil {
+		return "", fmt.Errorf("unable to find container of %v: %w", component, err)
+	}
+	names := strings.Fields(out)
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+func (c *Controller) Status(ctx context.Context, component Component) (Status, error) {
+	s := Status{Component: component, State: Missing}
+	name, err := c.container(ctx, component)
+	if err != nil || name == "" {
+		return s, err
+	}
+	s.Container = name
+	out, err := c.run(ctx, fmt.Sprintf("docker inspect -f '{{.State.Status}}' %v", gssh.ShellQuote(name)))
+	if err != nil {
+		return s, fmt.Errorf("unable to inspect %v: %w", name, err)
+	}
+	s.Detail = strings.TrimSpace(out)
+	switch s.Detail {
+	case "running":
+		s.State = Running
+	case "exited", "created", "dead":
+		s.State = Stopped
+	default:
+		s.State = Unknown
+	}
+
+	if s.State == Running {
+		s.Healthy = true
+		if check, ok := c.health[component]; ok {
+			if err = check(ctx); err != nil {
+				s.Healthy = false
+				s.Detail = err.Error()
+			}
+		}
+	}
+	return s, nil
+}
+
+// returns status of all components, error of single component is kept in its Detail
+func (c *Controller) StatusAll(ctx context.Context) []Status {
+	out := make([]Status, 0, len(Components))
+	for _, component := range Components {
+		s, err := c.Status(ctx, component)
+		if err != nil {
+			s.State, s.Detail = Unknown, err.Error()
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Start starts container of component and waits until it is healthy.
+// Components it depends on have to be running and healthy already
+func (c *Controller) Start(ctx context.Context, component Component) error {
+	s, err := c.Status(ctx, component)
+	if err != nil {
+		return err
+	}
+	if s.State == Missing {
+		return fmt.Errorf("%w: %v container does not exist, deploy the node first", ErrPrecondition, component)
+	}
+	for _, dep := range dependencies[component] {
+		ds, err := c.Status(ctx, dep)
+		if err != nil {
+			return err
+		}
+		if ds.State != Running || !ds.Healthy {
+			return fmt.Errorf("%w: %v requires %v to be running and healthy, %v is %v", ErrPrecondition, component, dep, dep, ds.State)
+		}
+	}
+	if s.State == Running && s.Healthy {
+		log.Printf("%v is already running", component)
+		return nil
+	}
+
+	if s.State != Running {
+		log.Printf("Starting %v (%v)", component, s.Container)
+		if _, err = c.run(ctx, "docker start "+gssh.ShellQuote(s.Container)); err != nil {
+			return fmt.Errorf("unable to start %v: %w", component, err)
+		}
+	}
+	return c.waitFor(ctx, component, func(s Status) bool { return s.State == Running && s.Healthy })
+}
+
+// Stop stops container of component, components which depend on it have to be stopped first
+func (c *Controller) Stop(ctx context.Context, component Component) error {
+	s, err := c.Status(ctx, component)
+	if err != nil {
+		return err
+	}
+	if s.State == Missing {
+		return fmt.Errorf("%w: %v container does not exist", ErrPrecondition, component)
+	}
+	for dependent, deps := range dependencies {
+		for _, dep := range deps {
+			if dep != component {
+				continue
+			}
+			ds, err := c.Status(ctx, dependent)
+			if err != nil {
+				return err
+			}
+			if ds.State == Running {
+				return fmt.Errorf("%w: %v depends on %v, stop %v first", ErrPrecondition, dependent, component, dependent)
+			}
+		}
+	}
+	if s.State == Stopped {
+		log.Printf("%v is already stopped", component)
+		return nil
+	}
+
+	log.Printf("Stopping %v (%v)", component, s.Container)
+	if _, err = c.run(ctx, "docker stop "+gssh.ShellQuote(s.Container)); err != nil {
+		return fmt.Errorf("unable to stop %v: %w", component, err)
+	}
+	return c.waitFor(ctx, component, func(s Status) bool { return s.State == Stopped })
+}
+
+// Restart restarts container in place, dependents keep running so interx reconnects to restarted sekai
+func (c *Controller) Restart(ctx context.Context, component Component) error {
+	s, err := c.Status(ctx, component)
+	if err != nil {
+		return err
+	}
+	if s.State == Missing {
+		return fmt.Errorf("%w: %v container does not exist, deploy the node first", ErrPrecondition, component)
+	}
+	log.Printf("Restarting %v (%v)", component, s.Container)
+	if _, err = c.run(ctx, "docker restart "+gssh.ShellQuote(s.Container)); err != nil {
+		return fmt.Errorf("unable to restart %v: %w", component, err)
+	}
+	return c.waitFor(ctx, component, func(s Status) bool { return s.State == Running && s.Healthy })
+}
+
+// polls status until done returns true or health timeout passes
+func (c *Controller) waitFor(ctx context.Context, component Component, done func(Status) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, c.HealthTimeout)
+	defer cancel()
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+
+	var last Status
+	for {
+		s, err := c.Status(ctx, component)
+		if err == nil {
+			if done(s) {
+				log.Printf("%v is %v", component, s.State)
+				return nil
+			}
+			last = s
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v is %v after %v: %v", ErrNotHealthy, component, last.State, c.HealthTimeout, last.Detail)
+		case <-ticker.C:
+		}
+	}
 }
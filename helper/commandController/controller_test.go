@@ -0,0 +1,196 @@
+package commandcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fake docker host, containers are named "sekin-<component>-1" and keep docker state
+type fakeDocker struct {
+	mu       sync.Mutex
+	states   map[Component]string
+	commands []string
+}
+
+func newFakeDocker(states map[Component]string) *fakeDocker {
+	return &fakeDocker{states: states}
+}
+
+func containerName(c Component) string {
+	return fmt.Sprintf("sekin-%v-1", c)
+}
+
+func (d *fakeDocker) run(_ context.Context, command string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for c, state := range d.states {
+		name := "'" + containerName(c) + "'"
+		switch command {
+		case fmt.Sprintf("docker ps -a --filter 'label=com.docker.compose.service=%v' --format '{{.Names}}'", c):
+			return containerName(c) + "\n", nil
+		case "docker inspect -f '{{.State.Status}}' " + name:
+			return state + "\n", nil
+		case "docker start " + name, "docker restart " + name:
+			d.commands = append(d.commands, command)
+			d.states[c] = "running"
+			return "", nil
+		case "docker stop " + name:
+			d.commands = append(d.commands, command)
+			d.states[c] = "exited"
+			return "", nil
+		}
+	}
+	if strings.HasPrefix(command, "docker ps") {
+		// container of the component does not exist
+		return "", nil
+	}
+	return "", fmt.Errorf("unexpected command %q", command)
+}
+
+// lifecycle commands sent so far
+func (d *fakeDocker) sent() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.commands...)
+}
+
+func newTestController(d *fakeDocker, health map[Component]HealthCheck) *Controller {
+	c := New(d.run, health)
+	c.HealthTimeout = 100 * time.Millisecond
+	c.PollInterval = 5 * time.Millisecond
+	return c
+}
+
+func TestControllerStartOrder(t *testing.T) {
+	d := newFakeDocker(map[Component]string{Sekai: "exited", Interx: "exited"})
+	c := newTestController(d, nil)
+	ctx := context.Background()
+
+	// interx needs running sekai, nothing is started
+	if err := c.Start(ctx, Interx); !errors.Is(err, ErrPrecondition) {
+		t.Fatalf("expected precondition error, got %v", err)
+	}
+	if sent := d.sent(); len(sent) != 0 {
+		t.Fatalf("commands sent although precondition failed: %v", sent)
+	}
+
+	for _, component := range []Component{Sekai, Interx} {
+		if err := c.Start(ctx, component); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// already running component is not started again
+	if err := c.Start(ctx, Sekai); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docker start 'sekin-sekai-1'", "docker start 'sekin-interx-1'"}
+	if sent := d.sent(); !reflect.DeepEqual(sent, want) {
+		t.Fatalf("sent %v, want %v", sent, want)
+	}
+}
+
+func TestControllerPreconditions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		states map[Component]string
+		action func(c *Controller) error
+	}{
+		{
+			name:   "start of missing container",
+			states: map[Component]string{},
+			action: func(c *Controller) error { return c.Start(ctx, Sekai) },
+		},
+		{
+			name:   "restart of missing container",
+			states: map[Component]string{Interx: "running"},
+			action: func(c *Controller) error { return c.Restart(ctx, Sekai) },
+		},
+		{
+			name:   "stop of sekai while interx runs",
+			states: map[Component]string{Sekai: "running", Interx: "running"},
+			action: func(c *Controller) error { return c.Stop(ctx, Sekai) },
+		},
+		{
+			name:   "start of interx while sekai is not healthy",
+			states: map[Component]string{Sekai: "running", Interx: "exited"},
+			action: func(c *Controller) error {
+				c.health = map[Component]HealthCheck{Sekai: func(context.Context) error { return errors.New("rpc down") }}
+				return c.Start(ctx, Interx)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newFakeDocker(tt.states)
+			if err := tt.action(newTestController(d, nil)); !errors.Is(err, ErrPrecondition) {
+				t.Fatalf("expected precondition error, got %v", err)
+			}
+			if sent := d.sent(); len(sent) != 0 {
+				t.Fatalf("commands sent although precondition failed: %v", sent)
+			}
+		})
+	}
+}
+
+func TestControllerWaitForHealth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("becomes healthy", func(t *testing.T) {
+		d := newFakeDocker(map[Component]string{Shidai: "exited"})
+		checks := 0
+		c := newTestController(d, map[Component]HealthCheck{Shidai: func(context.Context) error {
+			if checks++; checks < 3 {
+				return errors.New("starting")
+			}
+			return nil
+		}})
+		if err := c.Start(ctx, Shidai); err != nil {
+			t.Fatal(err)
+		}
+		if checks != 3 {
+			t.Fatalf("health checked %v times", checks)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		d := newFakeDocker(map[Component]string{Shidai: "exited"})
+		c := newTestController(d, map[Component]HealthCheck{Shidai: func(context.Context) error {
+			return errors.New("connection refused")
+		}})
+		start := time.Now()
+		err := c.Start(ctx, Shidai)
+		if !errors.Is(err, ErrNotHealthy) || !strings.Contains(err.Error(), "connection refused") {
+			t.Fatalf("expected not healthy error with last detail, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < c.HealthTimeout {
+			t.Fatalf("gave up after %v, before health timeout", elapsed)
+		}
+	})
+}
+
+func TestControllerStatusAll(t *testing.T) {
+	d := newFakeDocker(map[Component]string{Sekai: "running", Interx: "restarting", Shidai: "exited"})
+	c := newTestController(d, map[Component]HealthCheck{Sekai: func(context.Context) error { return nil }})
+	var got []string
+	for _, s := range c.StatusAll(context.Background()) {
+		got = append(got, fmt.Sprintf("%v:%v:%v", s.Component, s.State, s.Healthy))
+	}
+	want := []string{"sekai:running:true", "interx:unknown:false", "shidai:stopped:false", "syslog-ng:missing:false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	failing := New(func(context.Context, string) (string, error) { return "", ErrSudoPasswordRequired }, nil)
+	for _, s := range failing.StatusAll(context.Background()) {
+		if s.State != Unknown || !strings.Contains(s.Detail, "sudo password required") {
+			t.Fatalf("runner error is not kept in status: %+v", s)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package deployplan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/types"
+)
+
+// output of failed command included in error
+const failureOutputLimit = 1000
+
+// Command runs shell command on the node, Sudo passes password through stdin
+type Command struct {
+	Cmd  string
+	Sudo bool
+}
+
+func (a Command) Describe(v *Vars) string {
+	if a.Sudo {
+		return "sudo " + v.Preview(a.Cmd)
+	}
+	return v.Preview(a.Cmd)
+}
+
+func (a Command) Run(ctx context.Context, env *Env, v *Vars) error {
+	cmd, err := v.Expand(a.Cmd)
+	if err != nil {
+		return err
+	}
+	// last part of output is kept for error message
+	var tail bytes.Buffer
+	out := io.MultiWriter(env.output(), &tail)
+	var result gssh.ExecResult
+	if a.Sudo && env.Client.User() != "root" {
+		result, err = gssh.ExecSudo(ctx, env.Client, cmd, env.SudoPassword, out, out)
+	} else {
+		result, err = gssh.Exec(ctx, env.Client, cmd, out, out)
+	}
+	if err != nil {
+		output := strings.TrimSpace(v.Redact(tail.String()))
+		if len(output) > failureOutputLimit {
+			output = "..." + output[len(output)-failureOutputLimit:]
+		}
+		return fmt.Errorf("<%v> failed with exit code %v: %w\n%v", a.Describe(v), result.ExitCode, err, output)
+	}
+	return nil
+}
+
+// Download fetches URL on the local machine and keeps it as blob for Upload
+type Download struct {
+	URL  string
+	Into string
+}
+
+func (a Download) Describe(v *Vars) string {
+	return fmt.Sprintf("GET %v (local)", v.Preview(a.URL))
+}
+
+func (a Download) Run(ctx context.Context, _ *Env, v *Vars) error {
+	url, err := v.Expand(a.URL)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, httph.DefaultRequestTimeout)
+	defer cancel()
+	// error page must not end up in the script which is run with sudo
+	b, err := httph.Get(ctx, http.DefaultClient, url)
+	if err != nil {
+		return fmt.Errorf("unable to download <%v>: %w", url, err)
+	}
+	v.SetBlob(a.Into, b)
+	return nil
+}
+
+// Upload writes blob to the node over sftp
+type Upload struct {
+	From string
+	Path string
+}
+
+func (a Upload) Describe(v *Vars) string {
+	return fmt.Sprintf("sftp upload %v to %v", a.From, v.Preview(a.Path))
+}
+
+func (a Upload) Run(ctx context.Context, env *Env, v *Vars) error {
+	path, err := v.Expand(a.Path)
+	if err != nil {
+		return err
+	}
+	b, ok := v.Blob(a.From)
+	if !ok {
+		return fmt.Errorf("%v was not downloaded yet", a.From)
+	}
+	if err = gssh.SendFileSFTP(env.Client, b, path); err != nil {
+		return fmt.Errorf("unable to upload %v to <%v>: %w", a.From, path, err)
+	}
+	return nil
+}
+
+// ResolveVersions asks trusted node for sekai and interx versions, stored as sekai_version and interx_version
+type ResolveVersions struct {
+	IP         string
+	RPCPort    string
+	InterxPort string
+}
+
+func (a ResolveVersions) Describe(v *Vars) string {
+	return fmt.Sprintf("GET sekai and interx versions from %v (rpc %v, interx %v)", v.Preview(a.IP), v.Preview(a.RPCPort), v.Preview(a.InterxPort))
+}
+
+func (a ResolveVersions) Run(ctx context.Context, _ *Env, v *Vars) error {
+	values := make([]string, 3)
+	for i, s := range []string{a.IP, a.RPCPort, a.InterxPort} {
+		var err error
+		if values[i], err = v.Expand(s); err != nil {
+			return err
+		}
+	}
+	sekai, interx, err := httph.GetBinariesVersionsFromTrustedNode(values[0], values[1], values[2])
+	if err != nil {
+		return err
+	}
+	v.Set("sekai_version", sekai)
+	v.Set("interx_version", interx)
+	return nil
+}
+
+// WaitForShidai polls shidai status until it answers
+type WaitForShidai struct {
+	Timeout time.Duration
+}
+
+func (a WaitForShidai) Describe(*Vars) string {
+	return fmt.Sprintf("wait up to %v for shidai status", a.Timeout)
+}
+
+func (a WaitForShidai) Run(ctx context.Context, env *Env, _ *Vars) error {
+	ctx, cancel := context.WithTimeout(ctx, a.Timeout)
+	defer cancel()
+	for {
+		_, err := env.Shidai.Status(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("shidai did not start in %v: %w", a.Timeout, err)
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+// Join posts join command to shidai, Mnemonic is name of secret var.
+// Response of shidai is stored as join_output
+type Join struct {
+	IP         string
+	InterxPort string
+	RPCPort    string
+	P2PPort    string
+	Local      bool
+	Mnemonic   string
+}
+
+func (a Join) payload(expand func(string) (string, error)) (types.RequestDeployPayload, error) {
+	var err error
+	values := make([]string, 5)
+	for i, s := range []string{a.IP, a.InterxPort, a.RPCPort, a.P2PPort, "${" + a.Mnemonic + "}"} {
+		if values[i], err = expand(s); err != nil {
+			return types.RequestDeployPayload{}, err
+		}
+	}
+	args := types.Args{IP: values[0], Mnemonic: values[4], Local: a.Local}
+	for i, dst := range []*int{&args.InterxPort, &args.RPCPort, &args.P2PPort} {
+		// preview keeps zero for ports which are not known yet
+		if n, err := strconv.Atoi(values[i+1]); err == nil {
+			*dst = n
+		}
+	}
+	return types.RequestDeployPayload{Command: shidaiclient.JoinCommand, Args: args}, nil
+}
+
+func (a Join) Describe(v *Vars) string {
+	p, _ := a.payload(func(s string) (string, error) { return v.Preview(s), nil })
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(p)
+	return "POST shidai /api/execute " + strings.TrimSpace(b.String())
+}
+
+func (a Join) Run(ctx context.Context, env *Env, v *Vars) error {
+	p, err := a.payload(v.Expand)
+	if err != nil {
+		return err
+	}
+	out, err := env.Shidai.Join(ctx, p.Args)
+	if err != nil {
+		return fmt.Errorf("join failed: %v", v.Redact(err.Error()))
+	}
+	v.Set("join_output", v.Redact(out.Output))
+	return nil
+}
@@ -0,0 +1,71 @@
+package deployplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KiraCore/kensho/helper/httph"
+)
+
+func TestDownload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bootstrap.sh":
+			fmt.Fprint(w, "#!/bin/bash\necho ok\n")
+		case "/slow":
+			<-r.Context().Done()
+		default:
+			http.Error(w, "<html>not found</html>", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name     string
+		path     string
+		ctx      func() context.Context
+		wantBlob string
+		wantErr  func(error) bool
+	}{
+		{name: "script", path: "/bootstrap.sh", wantBlob: "#!/bin/bash\necho ok\n"},
+		{name: "error page is rejected", path: "/missing", wantErr: func(err error) bool {
+			var statusErr *httph.StatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+		}},
+		{name: "cancelled", path: "/slow", ctx: func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, wantErr: func(err error) bool { return errors.Is(err, context.Canceled) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx()
+			}
+			vars := NewVars()
+			err := Download{URL: srv.URL + tt.path, Into: "script"}.Run(ctx, &Env{}, vars)
+			blob, ok := vars.Blob("script")
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if ok {
+					t.Fatalf("blob was stored after failed download: %q", blob)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(blob) != tt.wantBlob {
+				t.Fatalf("blob %q, want %q", blob, tt.wantBlob)
+			}
+		})
+	}
+}
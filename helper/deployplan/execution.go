@@ -0,0 +1,167 @@
+package deployplan
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// rollback runs after ctx of the plan could be cancelled already, so it has its own deadline
+const rollbackTimeout = 2 * time.Minute
+
+type StepState string
+
+const (
+	Pending StepState = "pending"
+	Running StepState = "running"
+	Done    StepState = "done"
+	Failed  StepState = "failed"
+	// step failed and its rollback actions were run
+	RolledBack StepState = "rolled back"
+)
+
+// Progress is reported when state of a step changes
+type Progress struct {
+	Step  int
+	Name  string
+	State StepState
+	// error of failed step
+	Err error
+}
+
+// StepError is returned when step fails, execution can be resumed from it
+type StepError struct {
+	Step int
+	Name string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %v <%v> failed: %v", e.Step+1, e.Name, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// error with secrets removed from its message, cause is kept for errors.Is and errors.As
+type redactedError struct {
+	msg   string
+	cause error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.cause
+}
+
+// Execution runs steps of the plan in order and remembers where it stopped
+type Execution struct {
+	Plan *Plan
+	Vars *Vars
+	Env  *Env
+	// called from goroutine running the plan, can be nil
+	OnProgress func(Progress)
+
+	mu     sync.Mutex
+	states []StepState
+	next   int
+}
+
+func NewExecution(plan *Plan, vars *Vars, env *Env) *Execution {
+	states := make([]StepState, len(plan.Steps))
+	for i := range states {
+		states[i] = Pending
+	}
+	return &Execution{Plan: plan, Vars: vars, Env: env, states: states}
+}
+
+// States returns copy of current state of every step
+func (e *Execution) States() []StepState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]StepState(nil), e.states...)
+}
+
+// Finished reports whether all steps are done
+func (e *Execution) Finished() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.next >= len(e.Plan.Steps)
+}
+
+// Run runs steps from the first one which is not done yet, so calling it after failure resumes the plan.
+// Failed step is returned as *StepError, it is rolled back only when its actions were started
+func (e *Execution) Run(ctx context.Context) error {
+	for {
+		e.mu.Lock()
+		i := e.next
+		e.mu.Unlock()
+		if i >= len(e.Plan.Steps) {
+			return nil
+		}
+		step := e.Plan.Steps[i]
+
+		e.setState(i, Running, nil)
+		log.Printf("Deploy plan <%v>: running step %v <%v>", e.Plan.Name, i+1, step.Name)
+		if started, err := e.runStep(ctx, step); err != nil {
+			err = &redactedError{msg: e.Vars.Redact(err.Error()), cause: err}
+			log.Printf("Deploy plan <%v>: step %v <%v> failed: %v", e.Plan.Name, i+1, step.Name, err)
+			e.setState(i, Failed, err)
+			if started && len(step.Rollback) > 0 {
+				e.rollback(step)
+				e.setState(i, RolledBack, err)
+			}
+			return &StepError{Step: i, Name: step.Name, Err: err}
+		}
+		e.setState(i, Done, nil)
+
+		e.mu.Lock()
+		e.next = i + 1
+		e.mu.Unlock()
+	}
+}
+
+// started reports whether any action of the step was run, failed preconditions leave nothing to roll back
+func (e *Execution) runStep(ctx context.Context, step Step) (started bool, err error) {
+	for _, c := range step.Preconditions {
+		if err := c.Check(ctx, e.Env, e.Vars); err != nil {
+			return false, fmt.Errorf("precondition <%v> failed: %w", c.Description, err)
+		}
+	}
+	for i, a := range step.Actions {
+		if err := ctx.Err(); err != nil {
+			return i > 0, err
+		}
+		if err := a.Run(ctx, e.Env, e.Vars); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// rollback runs even when ctx was cancelled, errors are only logged since step already failed
+func (e *Execution) rollback(step Step) {
+	ctx, cancel := context.WithTimeout(context.Background(), rollbackTimeout)
+	defer cancel()
+	for i := len(step.Rollback) - 1; i >= 0; i-- {
+		a := step.Rollback[i]
+		if err := a.Run(ctx, e.Env, e.Vars); err != nil {
+			log.Printf("Deploy plan <%v>: rollback <%v> failed: %v", e.Plan.Name, a.Describe(e.Vars), e.Vars.Redact(err.Error()))
+		}
+	}
+}
+
+func (e *Execution) setState(i int, state StepState, err error) {
+	e.mu.Lock()
+	e.states[i] = state
+	e.mu.Unlock()
+	if e.OnProgress != nil {
+		e.OnProgress(Progress{Step: i, Name: e.Plan.Steps[i].Name, State: state, Err: err})
+	}
+}
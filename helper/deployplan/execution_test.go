@@ -0,0 +1,102 @@
+package deployplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// action which records its name when run
+type recordAction struct {
+	name string
+	err  error
+	log  *[]string
+}
+
+func (a recordAction) Describe(*Vars) string {
+	return a.name
+}
+
+func (a recordAction) Run(context.Context, *Env, *Vars) error {
+	*a.log = append(*a.log, a.name)
+	return a.err
+}
+
+func TestExecutionRollback(t *testing.T) {
+	failed := errors.New("boom")
+	tests := []struct {
+		name          string
+		precondition  error
+		actionErr     error
+		wantRun       []string
+		wantLastState StepState
+	}{
+		{
+			name:          "failed action is rolled back in reverse order",
+			actionErr:     failed,
+			wantRun:       []string{"first", "second", "undo second", "undo first"},
+			wantLastState: RolledBack,
+		},
+		{
+			name:          "failed precondition is not rolled back",
+			precondition:  failed,
+			wantRun:       nil,
+			wantLastState: Failed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var run []string
+			plan := &Plan{Name: "test", Steps: []Step{{
+				Name: "step",
+				Preconditions: []Check{{Description: "ready", Check: func(context.Context, *Env, *Vars) error {
+					return tt.precondition
+				}}},
+				Actions: []Action{
+					recordAction{name: "first", log: &run},
+					recordAction{name: "second", err: tt.actionErr, log: &run},
+				},
+				Rollback: []Action{
+					recordAction{name: "undo first", log: &run},
+					recordAction{name: "undo second", log: &run},
+				},
+			}}}
+			e := NewExecution(plan, NewVars(), &Env{})
+			var stepErr *StepError
+			if err := e.Run(context.Background()); !errors.As(err, &stepErr) || !errors.Is(err, failed) {
+				t.Fatalf("expected step error wrapping %v, got %v", failed, err)
+			}
+			if !reflect.DeepEqual(run, tt.wantRun) {
+				t.Fatalf("run %v, want %v", run, tt.wantRun)
+			}
+			if got := e.States(); got[0] != tt.wantLastState {
+				t.Fatalf("state %v, want %v", got[0], tt.wantLastState)
+			}
+		})
+	}
+}
+
+func TestExecutionErrorIsRedactedAndWrapped(t *testing.T) {
+	vars := NewVars()
+	vars.SetSecret("mnemonic", "abandon ability able")
+	var run []string
+	plan := &Plan{Name: "test", Steps: []Step{{
+		Name: "join",
+		Actions: []Action{recordAction{
+			name: "join",
+			err:  fmt.Errorf("join with abandon ability able: %w", context.Canceled),
+			log:  &run,
+		}},
+	}}}
+
+	err := NewExecution(plan, vars, &Env{}).Run(context.Background())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error chain is lost: %v", err)
+	}
+	if strings.Contains(err.Error(), "abandon") || !strings.Contains(err.Error(), redactedSecret) {
+		t.Fatalf("error is not redacted: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package deployplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/types"
+)
+
+// time shidai has to come up after bootstrap script finished
+const shidaiStartTimeout = 2 * time.Minute
+
+// JoinParams are inputs of the join plan
+type JoinParams struct {
+	TrustedIP  string
+	RPCPort    int
+	InterxPort int
+	P2PPort    int
+	Local      bool
+	Mnemonic   string
+	// user the plan runs as, bootstrap script is uploaded to its home
+	User string
+	// bootstrap sekin before join, needed when shidai is not running yet
+	Bootstrap bool
+}
+
+// NewJoinPlan returns plan which bootstraps sekin if requested and joins the trusted node through shidai
+func NewJoinPlan(p JoinParams) (*Plan, *Vars) {
+	v := NewVars()
+	v.Set("trusted_ip", p.TrustedIP)
+	v.Set("rpc_port", strconv.Itoa(p.RPCPort))
+	v.Set("interx_port", strconv.Itoa(p.InterxPort))
+	v.Set("p2p_port", strconv.Itoa(p.P2PPort))
+	v.SetSecret("mnemonic", p.Mnemonic)
+
+	plan := &Plan{Name: "join " + p.TrustedIP}
+	if p.Bootstrap {
+		path := fmt.Sprintf("/home/%v/bootstrap.sh", p.User)
+		if p.User == "root" {
+			path = "/root/bootstrap.sh"
+		}
+		v.Set("bootstrap_path", path)
+		script := gssh.ShellQuote(path)
+
+		plan.Steps = append(plan.Steps,
+			Step{
+				Name:    "Resolve versions of trusted node",
+				Actions: []Action{ResolveVersions{IP: "${trusted_ip}", RPCPort: "${rpc_port}", InterxPort: "${interx_port}"}},
+			},
+			Step{
+				Name:    "Download bootstrap script",
+				Actions: []Action{Download{URL: types.BOOTSTRAP_SCRIPT, Into: "bootstrap.sh"}},
+			},
+			Step{
+				Name:     "Upload bootstrap script",
+				Actions:  []Action{Upload{From: "bootstrap.sh", Path: "${bootstrap_path}"}},
+				Rollback: []Action{Command{Cmd: "rm -f " + script, Sudo: true}},
+			},
+			Step{
+				Name:          "Make bootstrap script executable",
+				Preconditions: []Check{sudoCheck},
+				Actions:       []Action{Command{Cmd: "chmod +x " + script + " 2>&1", Sudo: true}},
+			},
+			Step{
+				Name:          "Run bootstrap script",
+				Preconditions: []Check{sudoCheck},
+				Actions:       []Action{Command{Cmd: script + " --sekai=${sekai_version} --interx=${interx_version} 2>&1", Sudo: true}},
+			},
+			Step{
+				Name:    "Wait for shidai",
+				Actions: []Action{WaitForShidai{Timeout: shidaiStartTimeout}},
+			},
+		)
+	}
+
+	plan.Steps = append(plan.Steps, Step{
+		Name: "Join network",
+		Preconditions: []Check{{
+			Description: "shidai is reachable",
+			Check: func(ctx context.Context, env *Env, _ *Vars) error {
+				_, err := env.Shidai.Status(ctx)
+				return err
+			},
+		}},
+		Actions: []Action{Join{
+			IP:         "${trusted_ip}",
+			InterxPort: "${interx_port}",
+			RPCPort:    "${rpc_port}",
+			P2PPort:    "${p2p_port}",
+			Local:      p.Local,
+			Mnemonic:   "mnemonic",
+		}},
+	})
+	return plan, v
+}
+
+var sudoCheck = Check{
+	Description: "sudo is available",
+	Check: func(ctx context.Context, env *Env, _ *Vars) error {
+		if env.Client.User() == "root" || env.SudoPassword != "" {
+			return nil
+		}
+		noPassword, err := gssh.SudoNoPasswordRequired(ctx, env.Client)
+		if err != nil {
+			return err
+		}
+		if !noPassword {
+			return errors.New("sudo password is required")
+		}
+		return nil
+	},
+}
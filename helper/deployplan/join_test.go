@@ -0,0 +1,109 @@
+package deployplan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"github.com/KiraCore/kensho/types"
+)
+
+var testJoinParams = JoinParams{
+	TrustedIP:  "1.2.3.4",
+	RPCPort:    26657,
+	InterxPort: 11000,
+	P2PPort:    26656,
+	Local:      true,
+	Mnemonic:   "abandon ability able",
+	User:       "kira",
+}
+
+func TestJoinPlanRunsJoin(t *testing.T) {
+	fake := shidaiclient.NewFake()
+	fake.ExecuteOutput = "joined with abandon ability able"
+	plan, vars := NewJoinPlan(testJoinParams)
+	e := NewExecution(plan, vars, &Env{Shidai: fake})
+
+	if err := e.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Finished() {
+		t.Fatal("execution is not finished")
+	}
+	want := []any{types.RequestDeployPayload{Command: shidaiclient.JoinCommand, Args: types.Args{
+		IP: "1.2.3.4", InterxPort: 11000, RPCPort: 26657, P2PPort: 26656, Mnemonic: "abandon ability able", Local: true,
+	}}}
+	if got := fake.Executed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("executed %+v, want %+v", got, want)
+	}
+	if out, _ := vars.Get("join_output"); out != "joined with "+redactedSecret {
+		t.Fatalf("join output is not redacted: %q", out)
+	}
+}
+
+func TestJoinPlanPreconditionAndResume(t *testing.T) {
+	fake := shidaiclient.NewFake()
+	fake.Err = errors.New("connection refused")
+	plan, vars := NewJoinPlan(testJoinParams)
+	e := NewExecution(plan, vars, &Env{Shidai: fake})
+
+	var stepErr *StepError
+	if err := e.Run(context.Background()); !errors.As(err, &stepErr) || stepErr.Name != "Join network" {
+		t.Fatalf("expected join step to fail, got %v", err)
+	}
+	if len(fake.Executed()) != 0 {
+		t.Fatal("join was sent although shidai precondition failed")
+	}
+	if got := e.States(); got[len(got)-1] != Failed {
+		t.Fatalf("unexpected states %v", got)
+	}
+
+	fake.Err = nil
+	if err := e.Run(context.Background()); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(fake.Executed()) != 1 {
+		t.Fatalf("expected one join after resume, got %v", len(fake.Executed()))
+	}
+}
+
+func TestWaitForShidai(t *testing.T) {
+	fake := shidaiclient.NewFake()
+	if err := (WaitForShidai{Timeout: time.Second}).Run(context.Background(), &Env{Shidai: fake}, NewVars()); err != nil {
+		t.Fatalf("running shidai: %v", err)
+	}
+
+	fake.Err = errors.New("connection refused")
+	err := (WaitForShidai{Timeout: 50 * time.Millisecond}).Run(context.Background(), &Env{Shidai: fake}, NewVars())
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected timeout with last error, got %v", err)
+	}
+}
+
+func TestJoinPlanDryRunRedactsMnemonic(t *testing.T) {
+	params := testJoinParams
+	params.Bootstrap = true
+	plan, vars := NewJoinPlan(params)
+	preview := FormatDryRun(plan.Name, plan.DryRun(vars))
+
+	if strings.Contains(preview, params.Mnemonic) {
+		t.Fatalf("mnemonic leaked into dry-run:\n%v", preview)
+	}
+	for _, want := range []string{
+		`"mnemonic":"<redacted>"`,
+		"--sekai=<sekai_version> --interx=<interx_version>",
+		"sftp upload bootstrap.sh to /home/kira/bootstrap.sh",
+		"rollback: sudo rm -f '/home/kira/bootstrap.sh'",
+	} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("dry-run does not contain %q:\n%v", want, preview)
+		}
+	}
+	if len(plan.Steps) != 7 {
+		t.Fatalf("expected 7 steps with bootstrap, got %v", len(plan.Steps))
+	}
+}
@@ -0,0 +1,200 @@
+// Package deployplan describes deployments as a list of steps which can be previewed before anything
+// is executed on the node, run with per step progress and resumed from the step that failed.
+package deployplan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/KiraCore/kensho/helper/shidaiclient"
+	"golang.org/x/crypto/ssh"
+)
+
+const redactedSecret = "<redacted>"
+
+// Env is what actions need to reach the node
+type Env struct {
+	Client       *ssh.Client
+	Shidai       shidaiclient.Client
+	SudoPassword string
+	// output of remote commands, can be nil
+	Output io.Writer
+}
+
+func (e *Env) output() io.Writer {
+	if e.Output == nil {
+		return io.Discard
+	}
+	return e.Output
+}
+
+// Action is a single operation of a step
+type Action interface {
+	// Describe returns what the action does with secrets redacted, values not known yet are shown as <name>
+	Describe(v *Vars) string
+	Run(ctx context.Context, env *Env, v *Vars) error
+}
+
+// Check is a precondition of a step, step is not started when check fails
+type Check struct {
+	Description string
+	Check       func(ctx context.Context, env *Env, v *Vars) error
+}
+
+type Step struct {
+	Name          string
+	Preconditions []Check
+	Actions       []Action
+	// run in reverse order when one of actions fails, so the step can be run again
+	Rollback []Action
+}
+
+type Plan struct {
+	Name  string
+	Steps []Step
+}
+
+// StepPreview is a dry-run of a single step
+type StepPreview struct {
+	Name          string   `json:"name"`
+	Preconditions []string `json:"preconditions,omitempty"`
+	Actions       []string `json:"actions"`
+	Rollback      []string `json:"rollback,omitempty"`
+}
+
+// DryRun lists every remote command and request of the plan without running anything
+func (p *Plan) DryRun(v *Vars) []StepPreview {
+	out := make([]StepPreview, len(p.Steps))
+	for i, s := range p.Steps {
+		out[i] = StepPreview{Name: s.Name}
+		for _, c := range s.Preconditions {
+			out[i].Preconditions = append(out[i].Preconditions, c.Description)
+		}
+		for _, a := range s.Actions {
+			out[i].Actions = append(out[i].Actions, a.Describe(v))
+		}
+		for _, a := range s.Rollback {
+			out[i].Rollback = append(out[i].Rollback, a.Describe(v))
+		}
+	}
+	return out
+}
+
+// FormatDryRun renders preview as text
+func FormatDryRun(name string, steps []StepPreview) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan: %v\n", name)
+	for i, s := range steps {
+		fmt.Fprintf(&b, "\n%v. %v\n", i+1, s.Name)
+		for _, c := range s.Preconditions {
+			fmt.Fprintf(&b, "   check:    %v\n", c)
+		}
+		for _, a := range s.Actions {
+			fmt.Fprintf(&b, "   run:      %v\n", a)
+		}
+		for _, a := range s.Rollback {
+			fmt.Fprintf(&b, "   rollback: %v\n", a)
+		}
+	}
+	return b.String()
+}
+
+var varPattern = regexp.MustCompile(`\$\{([a-z0-9_]+)\}`)
+
+// Vars are inputs of the plan and values resolved by its steps, referenced as ${name}
+type Vars struct {
+	mu      sync.Mutex
+	values  map[string]string
+	secrets map[string]bool
+	blobs   map[string][]byte
+}
+
+func NewVars() *Vars {
+	return &Vars{values: map[string]string{}, secrets: map[string]bool{}, blobs: map[string][]byte{}}
+}
+
+func (v *Vars) Set(name, value string) {
+	v.mu.Lock()
+	v.values[name] = value
+	v.mu.Unlock()
+}
+
+// SetSecret sets value which is never shown in descriptions
+func (v *Vars) SetSecret(name, value string) {
+	v.mu.Lock()
+	v.values[name] = value
+	v.secrets[name] = true
+	v.mu.Unlock()
+}
+
+func (v *Vars) Get(name string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	value, ok := v.values[name]
+	return value, ok
+}
+
+func (v *Vars) SetBlob(name string, b []byte) {
+	v.mu.Lock()
+	v.blobs[name] = b
+	v.mu.Unlock()
+}
+
+func (v *Vars) Blob(name string) ([]byte, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	b, ok := v.blobs[name]
+	return b, ok
+}
+
+// Expand replaces references with values, unknown reference is an error
+func (v *Vars) Expand(s string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var missing []string
+	out := varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varPattern.FindStringSubmatch(ref)[1]
+		value, ok := v.values[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("values of %v are not known", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// Preview replaces references for display, secrets are redacted and unknown values shown as <name>
+func (v *Vars) Preview(s string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varPattern.FindStringSubmatch(ref)[1]
+		value, ok := v.values[name]
+		switch {
+		case v.secrets[name]:
+			return redactedSecret
+		case !ok:
+			return "<" + name + ">"
+		}
+		return value
+	})
+}
+
+// Redact removes values of secrets from s, used for command output and errors
+func (v *Vars) Redact(s string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for name := range v.secrets {
+		if secret := v.values[name]; secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedSecret)
+		}
+	}
+	return s
+}
@@ -0,0 +1,200 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+const metricPrefix = "kensho_"
+
+// values collected from a single host, nil fields could not be fetched and are not exported
+type HostData struct {
+	Host         string
+	Connected    bool
+	SSHRTT       time.Duration
+	Dashboard    *shidai.Dashboard
+	ShidaiStatus *shidai.Status
+	InterxStatus *interxendpoint.Status
+	Updated      time.Time
+}
+
+// Exporter keeps latest data of every host and serves it on /metrics
+type Exporter struct {
+	mu    sync.Mutex
+	hosts map[string]HostData
+}
+
+func New() *Exporter {
+	return &Exporter{hosts: map[string]HostData{}}
+}
+
+func (e *Exporter) Update(data HostData) {
+	if data.Updated.IsZero() {
+		data.Updated = time.Now()
+	}
+	e.mu.Lock()
+	e.hosts[data.Host] = data
+	e.mu.Unlock()
+}
+
+// Remove stops exporting host, used when host is removed from the fleet
+func (e *Exporter) Remove(host string) {
+	e.mu.Lock()
+	delete(e.hosts, host)
+	e.mu.Unlock()
+}
+
+// Serve starts http server with /metrics on addr, listen errors are returned right away.
+// Server is shut down when ctx is done.
+func (e *Exporter) Serve(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("unable to listen on <%v>: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	context.AfterFunc(ctx, func() { srv.Close() })
+
+	go func() {
+		log.Printf("Serving prometheus metrics on http://%v/metrics", listener.Addr())
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := WriteText(w, e.Families()); err != nil {
+		log.Printf("Unable to write metrics: %v", err)
+	}
+}
+
+// Families returns current values of all hosts, hosts are sorted by name
+func (e *Exporter) Families() []Family {
+	e.mu.Lock()
+	hosts := make([]HostData, 0, len(e.hosts))
+	for _, h := range e.hosts {
+		hosts = append(hosts, h)
+	}
+	e.mu.Unlock()
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+
+	b := newBuilder()
+	for _, h := range hosts {
+		host := Label{"host", h.Host}
+		b.add("host_up", "1 when ssh connection to the host is established", Gauge, boolValue(h.Connected), host)
+		b.add("last_update_timestamp_seconds", "Unix time of the last collection from the host", Gauge, float64(h.Updated.Unix()), host)
+		if h.SSHRTT > 0 {
+			b.add("ssh_rtt_seconds", "Round trip time of ssh keepalive", Gauge, h.SSHRTT.Seconds(), host)
+		}
+		if d := h.Dashboard; d != nil {
+			addDashboard(b, host, d)
+		}
+		if s := h.ShidaiStatus; s != nil {
+			for _, c := range []struct {
+				name string
+				info shidai.ComponentInfo
+			}{{"sekai", s.Sekai}, {"interx", s.Interx}, {"shidai", s.Shidai}, {"syslog-ng", s.Syslog}} {
+				b.add("component_info", "Component versions reported by shidai, value is 1", Gauge, 1,
+					host, Label{"component", c.name}, Label{"version", c.info.Version}, Label{"infra", strconv.FormatBool(c.info.Infra)})
+			}
+		}
+		if s := h.InterxStatus; s != nil {
+			info := s.InterxInfo
+			b.add("interx_info", "Interx status, value is 1", Gauge, 1,
+				host, Label{"chain_id", info.ChainID}, Label{"version", info.Version}, Label{"moniker", info.Moniker}, Label{"node_type", info.Node.NodeType})
+			b.addParsed("interx_latest_block_height", "Latest block height known to interx", Gauge, info.LatestBlockHeight, host)
+			b.add("interx_catching_up", "1 when interx reports node is catching up", Gauge, boolValue(info.CatchingUp), host)
+		}
+	}
+	return b.families
+}
+
+var validatorStatuses = []shidai.ValidatorStatus{shidai.Active, shidai.Paused, shidai.Inactive, shidai.Jailed}
+
+func addDashboard(b *builder, host Label, d *shidai.Dashboard) {
+	b.add("node_catching_up", "1 when node is catching up", Gauge, boolValue(d.CatchingUp), host)
+	b.add("seat_claim_available", "1 when validator seat can be claimed", Gauge, boolValue(d.SeatClaimAvailable), host)
+
+	status := shidai.ValidatorStatus(strings.ToUpper(d.ValidatorStatus))
+	for _, s := range validatorStatuses {
+		b.add("validator_status", "Validator status of the node, 1 for current status", Gauge, boolValue(s == status), host, Label{"status", string(s)})
+	}
+	b.addParsed("validator_streak", "Blocks produced in a row", Gauge, d.Streak, host)
+	b.addParsed("validator_mischance", "Blocks missed in a row", Gauge, d.Mischance, host)
+	b.addParsed("validator_mischance_confidence", "Mischance confidence", Gauge, d.MischanceConfidence, host)
+	b.addParsed("validator_produced_blocks", "Blocks produced by the validator", Gauge, d.ProducedBlocks, host)
+	b.addParsed("validator_last_present_block", "Last block produced by the validator", Gauge, d.LastProducedBlock, host)
+	b.addParsed("validator_rank", "Rank of the validator", Gauge, d.Top, host)
+	b.addParsed("validator_start_height", "Height at which validator started", Gauge, d.StartHeight, host)
+	b.addParsed("blocks", "Blocks reported by shidai dashboard", Gauge, d.Blocks, host)
+
+	for _, v := range []struct {
+		state string
+		count int
+	}{
+		{"active", d.ActiveValidators},
+		{"paused", d.PausedValidators},
+		{"inactive", d.InactiveValidators},
+		{"jailed", d.JailedValidators},
+		{"waiting", d.WaitingValidators},
+	} {
+		b.add("network_validators", "Validators of the network by state", Gauge, float64(v.count), host, Label{"state", v.state})
+	}
+}
+
+// groups samples into families keeping order in which families were first added
+type builder struct {
+	families []Family
+	index    map[string]int
+}
+
+func newBuilder() *builder {
+	return &builder{index: map[string]int{}}
+}
+
+func (b *builder) add(name, help string, t MetricType, value float64, labels ...Label) {
+	name = metricPrefix + name
+	i, ok := b.index[name]
+	if !ok {
+		i = len(b.families)
+		b.index[name] = i
+		b.families = append(b.families, Family{Name: name, Help: help, Type: t})
+	}
+	b.families[i].Samples = append(b.families[i].Samples, Sample{Labels: labels, Value: value})
+}
+
+// dashboard values are strings, values which are not numbers are skipped
+func (b *builder) addParsed(name, help string, t MetricType, value string, labels ...Label) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return
+	}
+	b.add(name, help, t, v, labels...)
+}
+
+func boolValue(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
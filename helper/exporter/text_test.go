@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+func TestWriteText(t *testing.T) {
+	families := []Family{
+		{
+			Name: "kensho_host_up",
+			Help: `Host "up" with \ and` + "\nsecond line",
+			Type: Gauge,
+			Samples: []Sample{
+				{Labels: []Label{{"host", `a\b "quoted"` + "\nnext"}}, Value: 1},
+				{Labels: []Label{{"host", "plain"}, {"zone", ""}}, Value: 0},
+			},
+		},
+		{Name: "kensho_empty", Help: "skipped without samples", Type: Gauge},
+		{
+			Name: "kensho_requests_total",
+			Help: "Requests",
+			Type: Counter,
+			Samples: []Sample{
+				{Value: 1.5e9},
+				{Labels: []Label{{"kind", "nan"}}, Value: math.NaN()},
+				{Labels: []Label{{"kind", "inf"}}, Value: math.Inf(1)},
+				{Labels: []Label{{"kind", "-inf"}}, Value: math.Inf(-1)},
+			},
+		},
+	}
+	want := `# HELP kensho_host_up Host "up" with \\ and\nsecond line
+# TYPE kensho_host_up gauge
+kensho_host_up{host="a\\b \"quoted\"\nnext"} 1
+kensho_host_up{host="plain",zone=""} 0
+# HELP kensho_requests_total Requests
+# TYPE kensho_requests_total counter
+kensho_requests_total 1.5e+09
+kensho_requests_total{kind="nan"} NaN
+kensho_requests_total{kind="inf"} +Inf
+kensho_requests_total{kind="-inf"} -Inf
+`
+	var b strings.Builder
+	if err := WriteText(&b, families); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Fatalf("got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	e := New()
+	updated := time.Unix(1700000000, 0)
+	e.Update(HostData{Host: "b", Connected: true, SSHRTT: 250 * time.Millisecond, Updated: updated})
+	e.Update(HostData{Host: "a", Updated: updated, Dashboard: &shidai.Dashboard{ValidatorStatus: "active", Streak: "12", Mischance: "n/a"}})
+	e.Update(HostData{Host: "gone", Updated: updated})
+	e.Remove("gone")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+
+	// families keep the order they were first added in, hosts are sorted within family
+	for _, want := range []string{
+		"# HELP kensho_host_up 1 when ssh connection to the host is established\n# TYPE kensho_host_up gauge\n" +
+			"kensho_host_up{host=\"a\"} 0\nkensho_host_up{host=\"b\"} 1\n",
+		"kensho_last_update_timestamp_seconds{host=\"a\"} 1.7e+09\n",
+		"kensho_validator_status{host=\"a\",status=\"ACTIVE\"} 1\nkensho_validator_status{host=\"a\",status=\"PAUSED\"} 0\n",
+		"kensho_validator_streak{host=\"a\"} 12\n",
+		"# TYPE kensho_ssh_rtt_seconds gauge\nkensho_ssh_rtt_seconds{host=\"b\"} 0.25\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%v", want, body)
+		}
+	}
+	for _, unwanted := range []string{"kensho_validator_mischance{", `host="gone"`} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("metrics contain %q:\n%v", unwanted, body)
+		}
+	}
+	if strings.Index(body, "# HELP kensho_host_up") > strings.Index(body, "# HELP kensho_ssh_rtt_seconds") {
+		t.Errorf("families are not in order of first use:\n%v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST returned %v", rec.Code)
+	}
+}
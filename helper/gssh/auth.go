@@ -0,0 +1,130 @@
+package gssh
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// connects to the running ssh-agent by SSH_AUTH_SOCK, hardware keys (yubikey, etc...) are exposed the same way
+func ConnectToSSHAgent() (agent.ExtendedAgent, net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, nil, fmt.Errorf("SSH_AUTH_SOCK is not set, is ssh-agent running?")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to ssh-agent <%v>: %w", socket, err)
+	}
+	return agent.NewClient(conn), conn, nil
+}
+
+// returns fingerprints and comments of keys held by ssh-agent
+func ListAgentKeys() ([]string, error) {
+	a, conn, err := ConnectToSSHAgent()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	keys, err := a.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, fmt.Sprintf("%v %v %v", k.Type(), Fingerprint(k), k.Comment))
+	}
+	return out, nil
+}
+
+func MakeSSH_ClientWithAgent(ipAndPort, user string, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
+	a, conn, err := ConnectToSSHAgent()
+	if err != nil {
+		return nil, err
+	}
+	// agent is only needed during handshake
+	defer conn.Close()
+
+	return dialSSH(ipAndPort, user, []ssh.AuthMethod{ssh.PublicKeysCallback(a.Signers)}, hostKeyCallback, jumpHosts...)
+}
+
+// returns agent backed auth method, closer has to be called after connection is established
+func NewAgentAuth() (ssh.AuthMethod, io.Closer, error) {
+	a, conn, err := ConnectToSSHAgent()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ssh.PublicKeysCallback(a.Signers), conn, nil
+}
+
+// returns private key auth method, passphrase can be nil for unencrypted keys
+func NewPrivKeyAuth(key, passphrase []byte) (ssh.AuthMethod, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) == 0 {
+		signer, err = ssh.ParsePrivateKey(key)
+	} else {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ssh.PublicKeys(signer), nil
+}
+
+// parses OpenSSH user certificate (id_ed25519-cert.pub) and checks if it is valid now
+func ParseUserCertificate(certBytes []byte) (*ssh.Certificate, error) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(certBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("<%v> key is not a certificate", pub.Type())
+	}
+	if cert.CertType != ssh.UserCert {
+		return nil, fmt.Errorf("certificate is not a user certificate")
+	}
+
+	now := uint64(time.Now().Unix())
+	if now < cert.ValidAfter {
+		return nil, fmt.Errorf("certificate is not valid yet, valid after %v", time.Unix(int64(cert.ValidAfter), 0))
+	}
+	if cert.ValidBefore != ssh.CertTimeInfinity && now >= cert.ValidBefore {
+		return nil, fmt.Errorf("certificate expired at %v", time.Unix(int64(cert.ValidBefore), 0))
+	}
+	return cert, nil
+}
+
+// authenticates with OpenSSH user certificate signed by CA, passphrase can be nil for unencrypted keys
+func MakeSSH_ClientWithCertificate(ipAndPort, user string, key, passphrase, certBytes []byte, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) == 0 {
+		signer, err = ssh.ParsePrivateKey(key)
+	} else {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := ParseUserCertificate(certBytes)
+	if err != nil {
+		return nil, err
+	}
+	certSigner, err := ssh.NewCertSigner(cert, signer)
+	if err != nil {
+		return nil, fmt.Errorf("certificate does not match private key: %w", err)
+	}
+	log.Printf("Using certificate <%v> with principals %v", cert.KeyId, cert.ValidPrincipals)
+
+	return dialSSH(ipAndPort, user, []ssh.AuthMethod{ssh.PublicKeys(certSigner)}, hostKeyCallback, jumpHosts...)
+}
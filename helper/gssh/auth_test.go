@@ -0,0 +1,195 @@
+package gssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func newTestSigner(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, priv
+}
+
+// starts in-process ssh server which only authenticates, returns its address and host key
+func startTestServer(t *testing.T, config *ssh.ServerConfig) (string, ssh.PublicKey) {
+	t.Helper()
+	hostSigner, _ := newTestSigner(t)
+	config.AddHostKey(hostSigner)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				defer sconn.Close()
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					ch.Reject(ssh.Prohibited, "test server")
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), hostSigner.PublicKey()
+}
+
+// serves keyring on unix socket and points SSH_AUTH_SOCK to it
+func startTestAgent(t *testing.T, keyring agent.Agent) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+}
+
+func TestMakeSSH_ClientWithAgent(t *testing.T) {
+	authorized, priv := newTestSigner(t)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), authorized.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	addr, hostKey := startTestServer(t, config)
+
+	t.Run("authorized key", func(t *testing.T) {
+		keyring := agent.NewKeyring()
+		if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+			t.Fatal(err)
+		}
+		startTestAgent(t, keyring)
+
+		client, err := MakeSSH_ClientWithAgent(addr, "kira", ssh.FixedHostKey(hostKey))
+		if err != nil {
+			t.Fatalf("connect with agent: %v", err)
+		}
+		client.Close()
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, other := newTestSigner(t)
+		keyring := agent.NewKeyring()
+		if err := keyring.Add(agent.AddedKey{PrivateKey: other}); err != nil {
+			t.Fatal(err)
+		}
+		startTestAgent(t, keyring)
+
+		if client, err := MakeSSH_ClientWithAgent(addr, "kira", ssh.FixedHostKey(hostKey)); err == nil {
+			client.Close()
+			t.Fatal("expected authentication to fail")
+		}
+	})
+
+	t.Run("no agent", func(t *testing.T) {
+		t.Setenv("SSH_AUTH_SOCK", "")
+		if _, err := MakeSSH_ClientWithAgent(addr, "kira", ssh.FixedHostKey(hostKey)); err == nil {
+			t.Fatal("expected error without SSH_AUTH_SOCK")
+		}
+	})
+}
+
+func TestMakeSSH_ClientWithCertificate(t *testing.T) {
+	ca, _ := newTestSigner(t)
+	otherCA, _ := newTestSigner(t)
+	userSigner, userPriv := newTestSigner(t)
+
+	block, err := ssh.MarshalPrivateKey(userPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userKey := pem.EncodeToMemory(block)
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), ca.PublicKey().Marshal())
+		},
+	}
+	addr, hostKey := startTestServer(t, &ssh.ServerConfig{PublicKeyCallback: checker.Authenticate})
+
+	now := time.Now()
+	sign := func(t *testing.T, signer ssh.Signer, principals []string, after, before time.Time) []byte {
+		t.Helper()
+		cert := &ssh.Certificate{
+			Key:             userSigner.PublicKey(),
+			CertType:        ssh.UserCert,
+			KeyId:           "test",
+			ValidPrincipals: principals,
+			ValidAfter:      uint64(after.Unix()),
+			ValidBefore:     uint64(before.Unix()),
+		}
+		if err := cert.SignCert(rand.Reader, signer); err != nil {
+			t.Fatal(err)
+		}
+		return ssh.MarshalAuthorizedKey(cert)
+	}
+
+	tests := []struct {
+		name    string
+		cert    []byte
+		wantErr bool
+	}{
+		{"valid", sign(t, ca, []string{"kira"}, now.Add(-time.Hour), now.Add(time.Hour)), false},
+		{"wrong principal", sign(t, ca, []string{"root"}, now.Add(-time.Hour), now.Add(time.Hour)), true},
+		{"unknown ca", sign(t, otherCA, []string{"kira"}, now.Add(-time.Hour), now.Add(time.Hour)), true},
+		{"expired", sign(t, ca, []string{"kira"}, now.Add(-2*time.Hour), now.Add(-time.Hour)), true},
+		{"not valid yet", sign(t, ca, []string{"kira"}, now.Add(time.Hour), now.Add(2*time.Hour)), true},
+		{"plain public key", ssh.MarshalAuthorizedKey(userSigner.PublicKey()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := MakeSSH_ClientWithCertificate(addr, "kira", userKey, nil, tt.cert, ssh.FixedHostKey(hostKey))
+			if client != nil {
+				client.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package gssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// time given to remote process to exit after SIGTERM, after that SIGKILL is sent and session is closed
+const cancelGracePeriod = 5 * time.Second
+
+var ErrCommandCancelled = errors.New("command cancelled")
+
+type ExecResult struct {
+	// -1 if process did not report exit status (killed, connection lost)
+	ExitCode int
+	// signal name without "SIG" prefix if process was killed by signal
+	Signal   string
+	Started  time.Time
+	Duration time.Duration
+}
+
+type Stream string
+
+const (
+	Stdout Stream = "stdout"
+	Stderr Stream = "stderr"
+)
+
+// OutputLine is a single line of command output tagged with the stream it was written to
+type OutputLine struct {
+	Stream Stream
+	Text   string
+}
+
+// runs command in a new session, stdout and stderr are streamed to separate writers (can be nil).
+// Cancelling ctx sends SIGTERM to the remote process and SIGKILL after grace period.
+// Non-zero exit code is returned as *ssh.ExitError together with filled result
+func Exec(ctx context.Context, client *ssh.Client, command string, stdout, stderr io.Writer) (ExecResult, error) {
+	return execSession(ctx, client, command, stdout, stderr, nil)
+}
+
+// prepare (can be nil) is called before command is started, e.g. to open stdin pipe
+func execSession(ctx context.Context, client *ssh.Client, command string, stdout, stderr io.Writer, prepare func(session *ssh.Session) error) (ExecResult, error) {
+	log.Printf("RUNNING CMD:\n%s", command)
+	result := ExecResult{ExitCode: -1, Started: time.Now()}
+
+	session, err := client.NewSession()
+	if err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("ssh EOF, probably ssh server was down, please restart Kensho: %w", err)
+		}
+		return result, fmt.Errorf("unable to create session: %w", err)
+	}
+	defer session.Close()
+
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if prepare != nil {
+		err = prepare(session)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	err = session.Start(command)
+	if err != nil {
+		return result, fmt.Errorf("unable to start command: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		log.Printf("Cancelling command, sending SIGTERM: %v", ctx.Err())
+		session.Signal(ssh.SIGTERM)
+		select {
+		case <-done:
+		case <-time.After(cancelGracePeriod):
+			log.Printf("Command did not exit after SIGTERM, sending SIGKILL")
+			session.Signal(ssh.SIGKILL)
+			session.Close()
+			<-done
+		}
+		result.Duration = time.Since(result.Started)
+		return result, fmt.Errorf("%w: %w", ErrCommandCancelled, ctx.Err())
+	}
+	result.Duration = time.Since(result.Started)
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+		result.Signal = exitErr.Signal()
+	}
+	return result, err
+}
+
+// ExecuteSSHCommandV4 runs command with Exec and streams its output line by line.
+// Output lines tagged with stream are sent to outputChan, outputChan is closed before result is sent.
+// Result contains whole stdout and stderr separately
+func ExecuteSSHCommandV4(ctx context.Context, client *ssh.Client, command string, outputChan chan<- OutputLine, resultChan chan<- ResultV2) {
+	executeWithOutput(ctx, outputChan, resultChan, func(stdout, stderr io.Writer) (ExecResult, error) {
+		return Exec(ctx, client, command, stdout, stderr)
+	})
+}
+
+// same as ExecuteSSHCommandV4 but command is run with sudo, see ExecSudo
+func ExecuteSSHSudoCommand(ctx context.Context, client *ssh.Client, command, password string, outputChan chan<- OutputLine, resultChan chan<- ResultV2) {
+	executeWithOutput(ctx, outputChan, resultChan, func(stdout, stderr io.Writer) (ExecResult, error) {
+		return ExecSudo(ctx, client, command, password, stdout, stderr)
+	})
+}
+
+func executeWithOutput(ctx context.Context, outputChan chan<- OutputLine, resultChan chan<- ResultV2, run func(stdout, stderr io.Writer) (ExecResult, error)) {
+	stdout := newLineWriter(ctx, Stdout, outputChan)
+	stderr := newLineWriter(ctx, Stderr, outputChan)
+
+	result, err := run(stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+	close(outputChan)
+	if err != nil {
+		log.Printf("Command finished with error after %v, exit code %v: %v", result.Duration, result.ExitCode, err)
+	}
+	resultChan <- ResultV2{
+		Err:      err,
+		ExitCode: result.ExitCode,
+		Signal:   result.Signal,
+		Stdout:   stdout.captured.String(),
+		Stderr:   stderr.captured.String(),
+		Duration: result.Duration,
+	}
+}
+
+// runs command and waits for the result, lines are not streamed
+func ExecuteSSHCommandAndWait(ctx context.Context, client *ssh.Client, command string) ResultV2 {
+	outputChan := make(chan OutputLine)
+	resultChan := make(chan ResultV2, 1)
+	go ExecuteSSHCommandV4(ctx, client, command, outputChan, resultChan)
+	for range outputChan {
+	}
+	return <-resultChan
+}
+
+// lineWriter sends every complete line written to it into the channel and keeps whole output
+type lineWriter struct {
+	ctx      context.Context
+	stream   Stream
+	ch       chan<- OutputLine
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	captured bytes.Buffer
+}
+
+func newLineWriter(ctx context.Context, stream Stream, ch chan<- OutputLine) *lineWriter {
+	return &lineWriter{ctx: ctx, stream: stream, ch: ch}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	w.captured.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf.Next(i + 1))
+		w.send(line[:len(line)-1])
+	}
+}
+
+// sends remaining incomplete line
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buf.Len() > 0 {
+		w.send(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+func (w *lineWriter) send(line string) {
+	log.Println(line)
+	select {
+	case w.ch <- OutputLine{Stream: w.stream, Text: line}:
+	case <-w.ctx.Done():
+	}
+}
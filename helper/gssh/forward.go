@@ -0,0 +1,234 @@
+package gssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type ForwardKind string
+
+const (
+	// listens locally, connections are opened from the remote host
+	LocalForward ForwardKind = "local"
+	// listens on the remote host, connections are opened from this machine
+	RemoteForward ForwardKind = "remote"
+)
+
+type ForwardSpec struct {
+	Name string
+	Kind ForwardKind
+	// address on this machine, e.g. "127.0.0.1:11000"
+	LocalAddr string
+	// address resolved by the ssh server, e.g. "localhost:11000"
+	RemoteAddr string
+}
+
+func (s ForwardSpec) String() string {
+	if s.Kind == RemoteForward {
+		return fmt.Sprintf("remote %v -> local %v", s.RemoteAddr, s.LocalAddr)
+	}
+	return fmt.Sprintf("local %v -> remote %v", s.LocalAddr, s.RemoteAddr)
+}
+
+func (s ForwardSpec) Validate() error {
+	if s.Kind != LocalForward && s.Kind != RemoteForward {
+		return fmt.Errorf("unknown forward kind <%v>", s.Kind)
+	}
+	for _, addr := range []string{s.LocalAddr, s.RemoteAddr} {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("address <%v> is not valid: %w", addr, err)
+		}
+	}
+	return nil
+}
+
+type ForwardStatus struct {
+	Spec ForwardSpec
+	// listener is running
+	Active bool
+	// currently open tunnelled connections
+	Connections int64
+	Err         error
+}
+
+// ForwardManager keeps port forwards of a single host, forwards are restarted when client is replaced after reconnect
+type ForwardManager struct {
+	mu       sync.Mutex
+	client   *ssh.Client
+	forwards []*forward
+}
+
+type forward struct {
+	spec        ForwardSpec
+	listener    net.Listener
+	connections atomic.Int64
+	err         error
+}
+
+func NewForwardManager() *ForwardManager {
+	return &ForwardManager{}
+}
+
+// replaces ssh client and restarts every forward with it
+func (m *ForwardManager) SetClient(client *ssh.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.client = client
+	for _, f := range m.forwards {
+		m.stop(f)
+		m.start(f)
+	}
+}
+
+// adds and starts forward, same local (or remote for remote forwards) address can be used only once
+func (m *ForwardManager) Add(spec ForwardSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.forwards {
+		if f.spec.Kind == spec.Kind && listenAddr(f.spec) == listenAddr(spec) {
+			return fmt.Errorf("<%v> is already forwarded", listenAddr(spec))
+		}
+	}
+	f := &forward{spec: spec}
+	m.start(f)
+	if f.err != nil {
+		return f.err
+	}
+	m.forwards = append(m.forwards, f)
+	return nil
+}
+
+func (m *ForwardManager) Remove(spec ForwardSpec) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, f := range m.forwards {
+		if f.spec == spec {
+			m.stop(f)
+			m.forwards = append(m.forwards[:i], m.forwards[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *ForwardManager) List() []ForwardStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ForwardStatus, 0, len(m.forwards))
+	for _, f := range m.forwards {
+		out = append(out, ForwardStatus{
+			Spec:        f.spec,
+			Active:      f.listener != nil,
+			Connections: f.connections.Load(),
+			Err:         f.err,
+		})
+	}
+	return out
+}
+
+// stops every forward, forwards are kept and started again on SetClient
+func (m *ForwardManager) CloseAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.forwards {
+		m.stop(f)
+	}
+}
+
+func (m *ForwardManager) start(f *forward) {
+	f.err = nil
+	if m.client == nil {
+		f.err = fmt.Errorf("not connected")
+		return
+	}
+	var l net.Listener
+	var err error
+	if f.spec.Kind == RemoteForward {
+		l, err = m.client.Listen("tcp", f.spec.RemoteAddr)
+	} else {
+		l, err = net.Listen("tcp", f.spec.LocalAddr)
+	}
+	if err != nil {
+		f.err = fmt.Errorf("unable to listen on <%v>: %w", listenAddr(f.spec), err)
+		log.Println(f.err)
+		return
+	}
+	f.listener = l
+	log.Printf("Started port forward %v", f.spec)
+	go m.serve(f, l, m.client)
+}
+
+func (m *ForwardManager) stop(f *forward) {
+	if f.listener != nil {
+		f.listener.Close()
+		f.listener = nil
+		log.Printf("Stopped port forward %v", f.spec)
+	}
+}
+
+func (m *ForwardManager) serve(f *forward, l net.Listener, client *ssh.Client) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			m.mu.Lock()
+			// listener was not closed by stop, e.g. remote listener died with ssh connection
+			if f.listener == l {
+				f.listener = nil
+				if !errors.Is(err, net.ErrClosed) && err != io.EOF {
+					f.err = err
+				}
+			}
+			m.mu.Unlock()
+			return
+		}
+		go func() {
+			f.connections.Add(1)
+			defer f.connections.Add(-1)
+
+			var target net.Conn
+			var dialErr error
+			if f.spec.Kind == RemoteForward {
+				target, dialErr = net.Dial("tcp", f.spec.LocalAddr)
+			} else {
+				target, dialErr = client.Dial("tcp", f.spec.RemoteAddr)
+			}
+			if dialErr != nil {
+				log.Printf("Port forward %v unable to connect: %v", f.spec, dialErr)
+				conn.Close()
+				return
+			}
+			pipeConnections(conn, target)
+		}()
+	}
+}
+
+// copies data both ways until one side is closed
+func pipeConnections(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go copyConn(a, b)
+	go copyConn(b, a)
+	<-done
+	a.Close()
+	b.Close()
+	<-done
+}
+
+func listenAddr(spec ForwardSpec) string {
+	if spec.Kind == RemoteForward {
+		return spec.RemoteAddr
+	}
+	return spec.LocalAddr
+}
@@ -1,12 +1,8 @@
 package gssh
 
 import (
-	"bufio"
-	"context"
 	"fmt"
-	"io"
-	"log"
-	"sync"
+	"time"
 
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
@@ -14,57 +10,46 @@ import (
 
 type ResultV2 struct {
 	Err error
+	// -1 if exit status is unknown
+	ExitCode int
+	// set if process was killed by signal, e.g. "TERM"
+	Signal   string
+	Stdout   string
+	Stderr   string
+	Duration time.Duration
 }
 
-func MakeSHH_ClientWithPassword(ipAndPort, user, psswrd string) (*ssh.Client, error) {
-	config := &ssh.ClientConfig{
-		User: user,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(psswrd),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-
-	// Connect to the SSH server
-	client, err := ssh.Dial("tcp", ipAndPort, config)
-	if err != nil {
-		return nil, err
-	}
-	return client, nil
+func MakeSHH_ClientWithPassword(ipAndPort, user, psswrd string, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
+	return dialSSH(ipAndPort, user, []ssh.AuthMethod{ssh.Password(psswrd)}, hostKeyCallback, jumpHosts...)
 }
 
-func MakeSSH_ClientWithPrivKey(ipAndPort, user string, key []byte) (*ssh.Client, error) {
+func MakeSSH_ClientWithPrivKey(ipAndPort, user string, key []byte, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
 	signer, err := ssh.ParsePrivateKey(key)
 	if err != nil {
 		return nil, err
 	}
-
-	config := &ssh.ClientConfig{
-		User: user,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-	client, err := ssh.Dial("tcp", ipAndPort, config)
-	if err != nil {
-		return nil, err
-	}
-	return client, nil
+	return dialSSH(ipAndPort, user, []ssh.AuthMethod{ssh.PublicKeys(signer)}, hostKeyCallback, jumpHosts...)
 }
 
-func MakeSSH_ClientWithPrivKeyAndPassphrase(ipAndPort, user string, key, passphrase []byte) (*ssh.Client, error) {
+func MakeSSH_ClientWithPrivKeyAndPassphrase(ipAndPort, user string, key, passphrase []byte, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
 	signer, err := ssh.ParsePrivateKeyWithPassphrase(key, passphrase)
 	if err != nil {
 		return nil, err
 	}
+	return dialSSH(ipAndPort, user, []ssh.AuthMethod{ssh.PublicKeys(signer)}, hostKeyCallback, jumpHosts...)
+}
+
+func dialSSH(ipAndPort, user string, auth []ssh.AuthMethod, hostKeyCallback ssh.HostKeyCallback, jumpHosts ...JumpHost) (*ssh.Client, error) {
 	config := &ssh.ClientConfig{
-		User: user,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}
+	if len(jumpHosts) > 0 {
+		return dialThroughJumpHosts(ipAndPort, config, jumpHosts)
 	}
+
+	// Connect to the SSH server
 	client, err := ssh.Dial("tcp", ipAndPort, config)
 	if err != nil {
 		return nil, err
@@ -83,180 +68,6 @@ func CheckIfPassphraseNeeded(privateKeyBytes []byte) (bool, error) {
 	return false, nil
 }
 
-func ExecuteSSHCommandV2(client *ssh.Client, command string, outputChan chan<- string, resultChan chan<- ResultV2) {
-	log.Printf("RUNNING CMD:\n%s", command)
-	var err error
-	ctx, cancel := context.WithCancel(context.Background())
-	defer func() {
-		resultChan <- ResultV2{Err: err}
-
-	}()
-	defer close(outputChan)
-	defer cancel() // This will be called if an early return occurs
-	session, err := client.NewSession()
-	if err != nil {
-		if err == io.EOF {
-			err = fmt.Errorf("ssh EOF, probably ssh server was down, please restart Kensho: %w", err)
-		}
-		log.Println("Error when creating new session: ", err.Error())
-		// cancel()
-		// close(outputChan)
-		// resultChan <- ResultV2{Err: err}
-		return
-	}
-	defer session.Close()
-
-	// Setting up stdout and stderr pipes
-	stdoutPipe, err := session.StdoutPipe()
-	if err != nil {
-		log.Println("Error when creating stdoutPipe: ", err.Error())
-		// cancel()
-		// close(outputChan)
-		// resultChan <- ResultV2{Err: err}
-		return
-	}
-	stderrPipe, err := session.StderrPipe()
-	if err != nil {
-		log.Println("Error when creating stderrPipe: ", err.Error())
-		// cancel()
-		// close(outputChan)
-		// resultChan <- ResultV2{Err: err}
-		return
-	}
-
-	var wg sync.WaitGroup
-	wg.Add(2)
-	// Start the command
-	err = session.Start(command)
-	if err != nil {
-		log.Println("Error when starting new session: ", err.Error())
-		// cancel()
-		// close(outputChan) // Close the channel on error
-		// resultChan <- ResultV2{Err: err}
-		return
-	}
-
-	// Read from stdout and stderr concurrently
-	go streamOutput(ctx, stdoutPipe, outputChan, &wg)
-	go streamOutput(ctx, stderrPipe, outputChan, &wg)
-
-	// go monitorConnection(ctx, client.Conn, outputChan, &wg)
-	err = session.Wait()
-	if err != nil {
-		// cancel()
-		// close(outputChan)
-		// resultChan <- ResultV2{Err: err}
-		return
-	}
-	wg.Wait()
-	// cancel()
-	// close(outputChan) // Close the channel when done
-
-}
-
-func streamOutput(ctx context.Context, reader io.Reader, outputChan chan<- string, wg *sync.WaitGroup) {
-	defer wg.Done()
-	scanner := bufio.NewScanner(reader)
-	for {
-		select {
-		case <-ctx.Done():
-			return // Exit if context is cancelled
-		default:
-			if scanner.Scan() {
-				select {
-				case outputChan <- scanner.Text():
-					log.Println(scanner.Text())
-				case <-ctx.Done():
-					return
-				}
-			} else {
-				return
-			}
-		}
-	}
-}
-
-func ExecuteSSHCommandV3(client *ssh.Client, command string, outputChan chan<- string, resultChan chan<- ResultV2) {
-	log.Printf("RUNNING CMD:\n%s", command)
-	var err error
-	ctx, cancel := context.WithCancel(context.Background())
-	defer func() {
-		resultChan <- ResultV2{Err: err}
-		cancel()
-	}()
-
-	session, err := client.NewSession()
-	if err != nil {
-		if err == io.EOF {
-			err = fmt.Errorf("ssh EOF, probably ssh server was down, please restart Kensho: %w", err)
-		}
-		log.Println("Error when creating new session: ", err.Error())
-		return
-	}
-	defer session.Close()
-
-	// Setting up stdout and stderr pipes
-	stdoutPipe, err := session.StdoutPipe()
-	if err != nil {
-		log.Println("Error when creating stdoutPipe: ", err.Error())
-		return
-	}
-	stderrPipe, err := session.StderrPipe()
-	if err != nil {
-		log.Println("Error when creating stderrPipe: ", err.Error())
-		return
-	}
-
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	// Start the command
-	err = session.Start(command)
-	if err != nil {
-		log.Println("Error when starting new session: ", err.Error())
-		return
-	}
-
-	// Read from stdout and stderr concurrently
-	go func() {
-		defer wg.Done()
-		streamOutputV2(ctx, stdoutPipe, outputChan)
-	}()
-	go func() {
-		defer wg.Done()
-		streamOutputV2(ctx, stderrPipe, outputChan)
-	}()
-
-	err = session.Wait()
-	if err != nil {
-		log.Println("Error when waiting for session to finish: ", err.Error())
-	}
-
-	wg.Wait()
-	close(outputChan)
-}
-
-func streamOutputV2(ctx context.Context, reader io.Reader, outputChan chan<- string) {
-	scanner := bufio.NewScanner(reader)
-	for {
-		select {
-		case <-ctx.Done():
-			return // Exit if context is cancelled
-		default:
-			if scanner.Scan() {
-				select {
-				case outputChan <- scanner.Text():
-					log.Println(scanner.Text())
-				case <-ctx.Done():
-					return
-				}
-			} else {
-				return
-			}
-		}
-	}
-}
-
 func MakeSSHsessionForTerminal(client *ssh.Client) (*ssh.Session, error) {
 	// Create a session
 	session, err := client.NewSession()
@@ -0,0 +1,91 @@
+package gssh
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/KiraCore/kensho/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// JumpHost is a single hop of ProxyJump chain, every hop is authenticated separately
+type JumpHost struct {
+	Address string
+	User    string
+	Auth    []ssh.AuthMethod
+}
+
+// parses ProxyJump style hop "user@host:port", port is optional
+func ParseJumpHostSpec(spec string) (user, address string, err error) {
+	spec = strings.TrimSpace(spec)
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 || at == len(spec)-1 {
+		return "", "", fmt.Errorf("jump host <%v> must be in user@host[:port] format", spec)
+	}
+	user, hostPort := spec[:at], spec[at+1:]
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		// no port specified
+		host, port = strings.Trim(hostPort, "[]"), strconv.Itoa(types.DEFAULT_SSH_PORT)
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("jump host <%v> has empty host", spec)
+	}
+	return user, net.JoinHostPort(host, port), nil
+}
+
+// dials target through the jump hosts chain, every hop host key is verified by hostKeyCallback
+func dialThroughJumpHosts(ipAndPort string, config *ssh.ClientConfig, jumpHosts []JumpHost) (*ssh.Client, error) {
+	var bastion *ssh.Client
+	for i, hop := range jumpHosts {
+		log.Printf("Connecting to jump host %v/%v <%v@%v>", i+1, len(jumpHosts), hop.User, hop.Address)
+		hopConfig := &ssh.ClientConfig{
+			User:            hop.User,
+			Auth:            hop.Auth,
+			HostKeyCallback: config.HostKeyCallback,
+		}
+		next, err := dialHop(bastion, hop.Address, hopConfig)
+		if err != nil {
+			if bastion != nil {
+				bastion.Close()
+			}
+			return nil, fmt.Errorf("failed to connect to jump host <%v>: %w", hop.Address, err)
+		}
+		bastion = next
+	}
+
+	client, err := dialHop(bastion, ipAndPort, config)
+	if err != nil {
+		bastion.Close()
+		return nil, fmt.Errorf("failed to connect to <%v> through jump hosts: %w", ipAndPort, err)
+	}
+	return client, nil
+}
+
+// opens ssh connection directly or tunnelled through previous hop,
+// previous hop is closed together with the new connection
+func dialHop(previous *ssh.Client, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if previous == nil {
+		return ssh.Dial("tcp", address, config)
+	}
+
+	conn, err := previous.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	go func() {
+		client.Wait()
+		previous.Close()
+	}()
+	return client, nil
+}
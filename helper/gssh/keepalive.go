@@ -0,0 +1,64 @@
+package gssh
+
+import (
+	"log"
+	"time"
+
+	"github.com/KiraCore/kensho/types"
+	"golang.org/x/crypto/ssh"
+)
+
+type KeepAliveConfig struct {
+	// how often keepalive@openssh.com is sent, also used as reply timeout
+	Interval time.Duration
+	// unanswered keepalives in a row after which connection is closed
+	MaxMisses int
+}
+
+func DefaultKeepAliveConfig() KeepAliveConfig {
+	return KeepAliveConfig{
+		Interval:  time.Duration(types.DEFAULT_KEEPALIVE_INTERVAL) * time.Second,
+		MaxMisses: types.DEFAULT_KEEPALIVE_MISSES,
+	}
+}
+
+// sends keepalive@openssh.com requests until client is closed, onReply (can be nil) receives round trip time of every answered request.
+// Half-open connection does not return any error by itself, so client is closed after MaxMisses unanswered requests in a row,
+// client.Wait() returns and in-flight sessions and tunnelled connections fail instead of hanging
+func KeepAlive(client *ssh.Client, config KeepAliveConfig, onReply func(rtt time.Duration)) {
+	if config.Interval <= 0 || config.MaxMisses <= 0 {
+		config = DefaultKeepAliveConfig()
+	}
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	misses := 0
+	for range ticker.C {
+		start := time.Now()
+		reply := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			reply <- err
+		}()
+
+		select {
+		case err := <-reply:
+			if err != nil {
+				// connection is already closed
+				return
+			}
+			misses = 0
+			if onReply != nil {
+				onReply(time.Since(start))
+			}
+		case <-time.After(config.Interval):
+			misses++
+			log.Printf("Keepalive to <%v> was not answered (%v/%v)", client.RemoteAddr(), misses, config.MaxMisses)
+			if misses >= config.MaxMisses {
+				log.Printf("Closing dead connection to <%v>", client.RemoteAddr())
+				client.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package gssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var ErrHostKeyRejected = errors.New("host key was not accepted by the user")
+
+// HostKeyMismatchError is returned when the host presents a key that differs from the pinned one.
+// This is never resolved automatically, the pinned key has to be revoked by the user first.
+type HostKeyMismatchError struct {
+	Host      string
+	Presented string
+	Pinned    []string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("HOST KEY MISMATCH for <%v>: presented key %v does not match pinned key(s) %v. Someone could be eavesdropping on you (man-in-the-middle attack) or the host key was changed. If you are sure the change is legit, revoke the pinned key in the known hosts manager and connect again",
+		e.Host, e.Presented, strings.Join(e.Pinned, ", "))
+}
+
+// KnownHost is a single entry of the known_hosts file.
+type KnownHost struct {
+	Line        int
+	Hosts       []string
+	KeyType     string
+	Fingerprint string
+}
+
+// KnownHosts is a known_hosts store in OpenSSH format managed by Kensho.
+type KnownHosts struct {
+	path string
+	mu   sync.Mutex
+}
+
+// HostKeyConfirmFunc is called for hosts that are not in the store yet (trust on first use).
+// Returning true pins the key.
+type HostKeyConfirmFunc func(hostname string, key ssh.PublicKey) bool
+
+func NewKnownHosts(path string) (*KnownHosts, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create known_hosts folder: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open known_hosts file: %w", err)
+	}
+	f.Close()
+	return &KnownHosts{path: path}, nil
+}
+
+func (k *KnownHosts) Path() string {
+	return k.path
+}
+
+// HostKeyCallback verifies host keys against the store.
+// Unknown hosts are passed to confirm, mismatching keys always fail with *HostKeyMismatchError.
+func (k *KnownHosts) HostKeyCallback(confirm HostKeyConfirmFunc) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		k.mu.Lock()
+		callback, err := knownhosts.New(k.path)
+		k.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to read known_hosts: %w", err)
+		}
+
+		err = callback(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+
+		fingerprint := Fingerprint(key)
+		if len(keyErr.Want) > 0 {
+			pinned := make([]string, 0, len(keyErr.Want))
+			for _, w := range keyErr.Want {
+				pinned = append(pinned, Fingerprint(w.Key))
+			}
+			log.Printf("Host key mismatch for <%v>, presented: %v, pinned: %v", hostname, fingerprint, pinned)
+			return &HostKeyMismatchError{Host: hostname, Presented: fingerprint, Pinned: pinned}
+		}
+
+		log.Printf("Unknown host <%v> with %v key %v", hostname, key.Type(), fingerprint)
+		if confirm == nil || !confirm(hostname, key) {
+			return ErrHostKeyRejected
+		}
+		return k.Add(hostname, key)
+	}
+}
+
+// Add pins the key for the hostname.
+func (k *KnownHosts) Add(hostname string, key ssh.PublicKey) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	f, err := os.OpenFile(k.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts file: %w", err)
+	}
+	defer f.Close()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("failed to write to known_hosts file: %w", err)
+	}
+	log.Printf("Pinned %v key %v for <%v>", key.Type(), Fingerprint(key), hostname)
+	return nil
+}
+
+// List returns all valid entries of the store, malformed lines are skipped.
+func (k *KnownHosts) List() ([]KnownHost, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	b, err := os.ReadFile(k.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []KnownHost
+	for i, line := range bytes.Split(b, []byte("\n")) {
+		entry, ok, err := parseKnownHost(line)
+		if err != nil {
+			log.Printf("Skipping malformed known_hosts line %v: %v", i+1, err)
+			continue
+		}
+		if ok {
+			entry.Line = i + 1
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+// Remove revokes the entry with the same hosts and key as the given one (as returned by List).
+// Entry is matched by content and not by line, so changes made after List do not revoke a different key
+func (k *KnownHosts) Remove(entry KnownHost) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	b, err := os.ReadFile(k.path)
+	if err != nil {
+		return err
+	}
+	lines := bytes.Split(b, []byte("\n"))
+	kept := lines[:0]
+	removed := 0
+	for _, line := range lines {
+		e, ok, err := parseKnownHost(line)
+		if err == nil && ok && e.KeyType == entry.KeyType && e.Fingerprint == entry.Fingerprint && slices.Equal(e.Hosts, entry.Hosts) {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%v key %v for <%v> is not pinned", entry.KeyType, entry.Fingerprint, strings.Join(entry.Hosts, ", "))
+	}
+
+	return os.WriteFile(k.path, bytes.Join(kept, []byte("\n")), 0600)
+}
+
+// parses single known_hosts line, ok is false for empty lines and comments
+func parseKnownHost(line []byte) (entry KnownHost, ok bool, err error) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] == '#' {
+		return KnownHost{}, false, nil
+	}
+	_, hosts, key, _, _, err := ssh.ParseKnownHosts(trimmed)
+	if err != nil {
+		return KnownHost{}, false, err
+	}
+	return KnownHost{
+		Hosts:       hosts,
+		KeyType:     key.Type(),
+		Fingerprint: Fingerprint(key),
+	}, true, nil
+}
+
+func Fingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
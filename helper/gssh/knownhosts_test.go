@@ -0,0 +1,83 @@
+package gssh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKnownHostsRemove(t *testing.T) {
+	k, err := NewKnownHosts(filepath.Join(t.TempDir(), "known_hosts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := newTestSigner(t)
+	second, _ := newTestSigner(t)
+	if err = k.Add("first.example:22", first.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+	if err = k.Add("second.example:22", second.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := k.List()
+	if err != nil || len(listed) != 2 {
+		t.Fatalf("listed %+v, %v", listed, err)
+	}
+	target := listed[1]
+
+	// first entry is revoked after List, so the line of target moves up
+	if err = k.Remove(listed[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err = k.Remove(target); err != nil {
+		t.Fatal(err)
+	}
+	left, err := k.List()
+	if err != nil || len(left) != 0 {
+		t.Fatalf("left %+v, %v", left, err)
+	}
+
+	if err = k.Remove(target); err == nil {
+		t.Fatal("removing entry which is not pinned does not fail")
+	}
+}
+
+func TestKnownHostsRemoveKeepsOtherLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	k, err := NewKnownHosts(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := newTestSigner(t)
+	other, _ := newTestSigner(t)
+	if err = os.WriteFile(path, []byte("# comment\nnot a key line\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// same host with two keys, only the listed one is revoked
+	if err = k.Add("host.example:22", key.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+	if err = k.Add("host.example:22", other.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+	listed, err := k.List()
+	if err != nil || len(listed) != 2 {
+		t.Fatalf("listed %+v, %v", listed, err)
+	}
+	if err = k.Remove(listed[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	left, err := k.List()
+	if err != nil || len(left) != 1 || left[0].Fingerprint != Fingerprint(other.PublicKey()) {
+		t.Fatalf("left %+v, %v", left, err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# comment\nnot a key line\n"; string(b[:len(want)]) != want {
+		t.Fatalf("comment and malformed line were not kept:\n%s", b)
+	}
+}
@@ -0,0 +1,208 @@
+package gssh
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// ProgressFunc receives transferred and total bytes, total is -1 if unknown
+type ProgressFunc func(done, total int64)
+
+// RemoteFile is a single entry of remote directory
+type RemoteFile struct {
+	Name    string
+	Path    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	IsDir   bool
+}
+
+// SFTP is a file session over existing ssh client, has to be closed after use
+type SFTP struct {
+	client *sftp.Client
+}
+
+func OpenSFTP(client *ssh.Client) (*SFTP, error) {
+	c, err := sftp.NewClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SFTP client: %w", err)
+	}
+	return &SFTP{client: c}, nil
+}
+
+func (s *SFTP) Close() error {
+	return s.client.Close()
+}
+
+// returns absolute path of the user home (sftp working directory)
+func (s *SFTP) HomeDir() (string, error) {
+	return s.client.Getwd()
+}
+
+// returns directory entries, directories first, then sorted by name
+func (s *SFTP) List(dir string) ([]RemoteFile, error) {
+	infos, err := s.client.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list <%v>: %w", dir, err)
+	}
+	out := make([]RemoteFile, 0, len(infos))
+	for _, info := range infos {
+		out = append(out, newRemoteFile(path.Join(dir, info.Name()), info))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IsDir != out[j].IsDir {
+			return out[i].IsDir
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func (s *SFTP) Stat(p string) (RemoteFile, error) {
+	info, err := s.client.Stat(p)
+	if err != nil {
+		return RemoteFile{}, fmt.Errorf("unable to stat <%v>: %w", p, err)
+	}
+	return newRemoteFile(p, info), nil
+}
+
+// copies remote file to local path, local file is overwritten
+func (s *SFTP) Download(remotePath, localPath string, progress ProgressFunc) error {
+	log.Printf("Downloading <%v> to <%v>", remotePath, localPath)
+	src, err := s.client.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("unable to open remote file <%v>: %w", remotePath, err)
+	}
+	defer src.Close()
+
+	total := int64(-1)
+	if info, err := src.Stat(); err == nil {
+		total = info.Size()
+	}
+
+	dst, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to create local file <%v>: %w", localPath, err)
+	}
+	defer dst.Close()
+
+	// sftp file implements WriterTo with concurrent requests, so progress is tracked on the writer side
+	_, err = io.Copy(&progressWriter{w: dst, total: total, progress: progress}, src)
+	if err != nil {
+		return fmt.Errorf("unable to download <%v>: %w", remotePath, err)
+	}
+	return dst.Close()
+}
+
+// copies local file to remote path, remote file is overwritten
+func (s *SFTP) Upload(localPath, remotePath string, progress ProgressFunc) error {
+	log.Printf("Uploading <%v> to <%v>", localPath, remotePath)
+	src, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to open local file <%v>: %w", localPath, err)
+	}
+	defer src.Close()
+
+	total := int64(-1)
+	if info, err := src.Stat(); err == nil {
+		total = info.Size()
+	}
+
+	dst, err := s.client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("unable to create remote file <%v>: %w", remotePath, err)
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, &progressReader{r: src, total: total, progress: progress})
+	if err != nil {
+		return fmt.Errorf("unable to upload <%v>: %w", localPath, err)
+	}
+	return dst.Close()
+}
+
+func (s *SFTP) Rename(oldPath, newPath string) error {
+	log.Printf("Renaming <%v> to <%v>", oldPath, newPath)
+	// posix rename overwrites existing target, plain rename fails on it
+	if _, ok := s.client.HasExtension("posix-rename@openssh.com"); ok {
+		return s.client.PosixRename(oldPath, newPath)
+	}
+	return s.client.Rename(oldPath, newPath)
+}
+
+func (s *SFTP) Chmod(p string, mode os.FileMode) error {
+	log.Printf("Changing mode of <%v> to %v", p, mode)
+	return s.client.Chmod(p, mode)
+}
+
+// removes file or directory with all its content
+func (s *SFTP) Delete(p string) error {
+	log.Printf("Deleting <%v>", p)
+	info, err := s.client.Lstat(p)
+	if err != nil {
+		return fmt.Errorf("unable to stat <%v>: %w", p, err)
+	}
+	if info.IsDir() {
+		return s.client.RemoveAll(p)
+	}
+	return s.client.Remove(p)
+}
+
+func (s *SFTP) Mkdir(p string) error {
+	log.Printf("Creating directory <%v>", p)
+	return s.client.MkdirAll(p)
+}
+
+func newRemoteFile(p string, info os.FileInfo) RemoteFile {
+	return RemoteFile{
+		Name:    info.Name(),
+		Path:    p,
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
+
+// progressReader reports every read to progress func
+type progressReader struct {
+	r        io.Reader
+	done     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.progress != nil {
+		p.progress(p.done, p.total)
+	}
+	return n, err
+}
+
+// progressWriter reports every write to progress func
+type progressWriter struct {
+	w        io.Writer
+	done     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	if p.progress != nil {
+		p.progress(p.done, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,134 @@
+// Package sshtest provides in-process ssh server for tests of code which reaches the node through ssh tunnels,
+// e.g. shidai or interx clients talking to an httptest server.
+package sshtest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// payload of direct-tcpip channel, RFC 4254 section 7.2
+type directTCPIP struct {
+	Host       string
+	Port       uint32
+	OriginHost string
+	OriginPort uint32
+}
+
+// NewClient starts server which accepts any user and forwards direct-tcpip channels to local addresses,
+// returned client and server are closed when test ends
+func NewClient(t testing.TB) *ssh.Client {
+	t.Helper()
+	address, hostKey := NewServer(t, &ssh.ServerConfig{NoClientAuth: true})
+	client, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            "kira",
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// NewServer starts server which authenticates clients with config and forwards direct-tcpip channels
+// to local addresses. Host key is generated and added to config, server is closed when test ends
+func NewServer(t testing.TB, config *ssh.ServerConfig) (address string, hostKey ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.AddHostKey(hostSigner)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		l.Close()
+		wg.Wait()
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				serve(conn, config)
+			}()
+		}
+	}()
+	return l.Addr().String(), hostSigner.PublicKey()
+}
+
+func serve(conn net.Conn, config *ssh.ServerConfig) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "direct-tcpip" {
+			newCh.Reject(ssh.UnknownChannelType, "only direct-tcpip is supported")
+			continue
+		}
+		var target directTCPIP
+		if err := ssh.Unmarshal(newCh.ExtraData(), &target); err != nil {
+			newCh.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		go forward(newCh, net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	}
+}
+
+func forward(newCh ssh.NewChannel, address string) {
+	dst, err := net.Dial("tcp", address)
+	if err != nil {
+		newCh.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		dst.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(dst, ch)
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(ch, dst)
+		ch.CloseWrite()
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+	ch.Close()
+	dst.Close()
+}
@@ -0,0 +1,211 @@
+package gssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// custom sudo prompt, password is written to stdin only after this marker appears on stderr
+const sudoPromptMarker = "[kensho-sudo-prompt]"
+
+const redactedSecret = "********"
+
+var ErrSudoPasswordRejected = errors.New("sudo password was rejected")
+
+// quotes string for POSIX shell, safe for any content including quotes
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// replaces every occurrence of secrets in s
+func Redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedSecret)
+		}
+	}
+	return s
+}
+
+// wraps command so sudo reads password from stdin and prints known prompt to stderr.
+// Command itself reads /dev/null, stdin of the session stays open for sudo and would never reach EOF
+func SudoCommand(command string) string {
+	return fmt.Sprintf("sudo -S -p %v -- sh -c %v", ShellQuote(sudoPromptMarker), ShellQuote("exec </dev/null\n"+command))
+}
+
+// runs command with sudo, password is written to the session stdin when sudo asks for it,
+// so it never appears in remote process list or in command logs.
+// Password is not sent again if sudo asks twice, ErrSudoPasswordRejected is returned instead
+func ExecSudo(ctx context.Context, client *ssh.Client, command, password string, stdout, stderr io.Writer) (ExecResult, error) {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	redactedStdout := &redactingWriter{w: stdout, secret: []byte(password)}
+	redactedStderr := &redactingWriter{w: stderr, secret: []byte(password)}
+	watcher := &sudoPromptWatcher{out: redactedStderr, password: password}
+	result, err := execSession(ctx, client, SudoCommand(command), redactedStdout, watcher,
+		func(session *ssh.Session) error {
+			stdin, err := session.StdinPipe()
+			if err != nil {
+				return fmt.Errorf("unable to open stdin: %w", err)
+			}
+			watcher.stdin = stdin
+			return nil
+		})
+	watcher.Flush()
+	redactedStderr.Flush()
+	redactedStdout.Flush()
+	if watcher.Rejected() {
+		return result, fmt.Errorf("%w: %w", ErrSudoPasswordRejected, err)
+	}
+	return result, err
+}
+
+// checks if user can run sudo without password (NOPASSWD in sudoers)
+func SudoNoPasswordRequired(ctx context.Context, client *ssh.Client) (bool, error) {
+	result, err := Exec(ctx, client, "sudo -n true", nil, nil)
+	switch {
+	case err == nil:
+		return true, nil
+	case result.ExitCode > 0:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unable to check sudo: %w", err)
+	}
+}
+
+// sudoPromptWatcher passes stderr through without sudo prompt and answers the prompt with password
+type sudoPromptWatcher struct {
+	out      io.Writer
+	password string
+	stdin    io.WriteCloser
+
+	mu      sync.Mutex
+	pending []byte
+	prompts int
+}
+
+func (w *sudoPromptWatcher) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	marker := []byte(sudoPromptMarker)
+	for {
+		i := bytes.Index(w.pending, marker)
+		if i < 0 {
+			break
+		}
+		if _, err := w.out.Write(w.pending[:i]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[i+len(marker):]
+		w.answerPrompt()
+	}
+
+	// tail can be the beginning of the marker, it is kept until next write
+	keep := min(len(w.pending), len(marker)-1)
+	for keep > 0 && !bytes.HasPrefix(marker, w.pending[len(w.pending)-keep:]) {
+		keep--
+	}
+	if flush := len(w.pending) - keep; flush > 0 {
+		if _, err := w.out.Write(w.pending[:flush]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[flush:]
+	}
+	return len(p), nil
+}
+
+func (w *sudoPromptWatcher) answerPrompt() {
+	w.prompts++
+	if w.stdin == nil {
+		return
+	}
+	if w.prompts > 1 {
+		log.Println("sudo asked for password again, password was rejected")
+		w.stdin.Close()
+		return
+	}
+	log.Println("sudo asked for password, sending it to stdin")
+	if _, err := io.WriteString(w.stdin, w.password+"\n"); err != nil {
+		log.Printf("Unable to send sudo password: %v", err)
+	}
+}
+
+func (w *sudoPromptWatcher) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		w.out.Write(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *sudoPromptWatcher) Rejected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.prompts > 1
+}
+
+// redactingWriter hides secret in everything written through it. Secret can be split between writes,
+// so tail which can be the beginning of the secret is kept until next write or Flush
+type redactingWriter struct {
+	w      io.Writer
+	secret []byte
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if len(r.secret) == 0 {
+		return r.w.Write(p)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, p...)
+	var out []byte
+	for {
+		i := bytes.Index(r.pending, r.secret)
+		if i < 0 {
+			break
+		}
+		out = append(out, r.pending[:i]...)
+		out = append(out, redactedSecret...)
+		r.pending = r.pending[i+len(r.secret):]
+	}
+
+	keep := min(len(r.pending), len(r.secret)-1)
+	for keep > 0 && !bytes.HasPrefix(r.secret, r.pending[len(r.pending)-keep:]) {
+		keep--
+	}
+	out = append(out, r.pending[:len(r.pending)-keep]...)
+	r.pending = append([]byte(nil), r.pending[len(r.pending)-keep:]...)
+	if len(out) > 0 {
+		if _, err := r.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// writes kept tail, it is not the secret since the stream ended
+func (r *redactingWriter) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) > 0 {
+		r.w.Write(r.pending)
+		r.pending = nil
+	}
+}
@@ -0,0 +1,44 @@
+package gssh
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{"single write", []string{"pass is hunter2!"}, "pass is ********!"},
+		{"split secret", []string{"pass is hun", "ter2!"}, "pass is ********!"},
+		{"byte by byte", strings.Split("a hunter2 b hunter2", ""), "a ******** b ********"},
+		{"prefix without secret", []string{"hunt", "ing"}, "hunting"},
+		{"tail flushed", []string{"ends with hunt"}, "ends with hunt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := &redactingWriter{w: &out, secret: []byte("hunter2")}
+			for _, c := range tt.chunks {
+				if n, err := w.Write([]byte(c)); err != nil || n != len(c) {
+					t.Fatalf("Write(%q) = %v, %v", c, n, err)
+				}
+			}
+			w.Flush()
+			if out.String() != tt.want {
+				t.Fatalf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSudoCommand(t *testing.T) {
+	got := SudoCommand("echo 'hi'")
+	want := `sudo -S -p '[kensho-sudo-prompt]' -- sh -c 'exec </dev/null` + "\n" + `echo '"'"'hi'"'"''`
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
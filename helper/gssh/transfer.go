@@ -0,0 +1,304 @@
+package gssh
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	transferChunkSize = 1 << 20
+	// not finished transfers are kept under this suffix, next transfer continues from their size
+	partialFileSuffix = ".part"
+
+	// long enough to survive reconnect of the host
+	transferMaxAttempts = 20
+	transferRetryDelay  = 5 * time.Second
+)
+
+var ErrChecksumMismatch = errors.New("sha256 checksum mismatch")
+
+type TransferDirection int
+
+const (
+	Upload TransferDirection = iota
+	Download
+)
+
+type Transfer struct {
+	Direction  TransferDirection
+	LocalPath  string
+	RemotePath string
+}
+
+func (t Transfer) String() string {
+	if t.Direction == Download {
+		return fmt.Sprintf("%v -> %v", t.RemotePath, t.LocalPath)
+	}
+	return fmt.Sprintf("%v -> %v", t.LocalPath, t.RemotePath)
+}
+
+// returns client for the next transfer attempt, after reconnect it is a new client
+type ClientProvider func() (*ssh.Client, error)
+
+// transfers file in chunks, interrupted transfer is resumed from the size of partial file on the destination.
+// Result is verified by comparing sha256 of both ends and renamed to destination path only after that.
+// Failed attempts are retried with client from getClient, checksum mismatch and ctx cancel are not retried
+func TransferFile(ctx context.Context, getClient ClientProvider, t Transfer, progress ProgressFunc) error {
+	var err error
+	for attempt := 1; attempt <= transferMaxAttempts; attempt++ {
+		var client *ssh.Client
+		client, err = getClient()
+		if err == nil {
+			err = transferOnce(ctx, client, t, progress)
+		}
+		if err == nil || ctx.Err() != nil || errors.Is(err, ErrChecksumMismatch) {
+			break
+		}
+		log.Printf("Transfer <%v> failed (attempt %v/%v), resuming in %v: %v", t, attempt, transferMaxAttempts, transferRetryDelay, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(transferRetryDelay):
+		}
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("transfer <%v> cancelled: %w", t, ctx.Err())
+	}
+	return err
+}
+
+// runs transfers with at most `parallel` of them at the same time, returns error for every transfer (nil on success)
+func TransferAll(ctx context.Context, getClient ClientProvider, transfers []Transfer, parallel int, progress func(i int, done, total int64)) []error {
+	if parallel < 1 {
+		parallel = 1
+	}
+	errs := make([]error, len(transfers))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, t := range transfers {
+		wg.Add(1)
+		go func(i int, t Transfer) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = TransferFile(ctx, getClient, t, func(done, total int64) {
+				if progress != nil {
+					progress(i, done, total)
+				}
+			})
+		}(i, t)
+	}
+	wg.Wait()
+	return errs
+}
+
+func transferOnce(ctx context.Context, client *ssh.Client, t Transfer, progress ProgressFunc) error {
+	sftpClient, err := sftp.NewClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create SFTP client: %w", err)
+	}
+	defer sftpClient.Close()
+
+	if t.Direction == Download {
+		return download(ctx, client, sftpClient, t, progress)
+	}
+	return upload(ctx, client, sftpClient, t, progress)
+}
+
+func upload(ctx context.Context, client *ssh.Client, sftpClient *sftp.Client, t Transfer, progress ProgressFunc) error {
+	src, err := os.Open(t.LocalPath)
+	if err != nil {
+		return fmt.Errorf("unable to open local file <%v>: %w", t.LocalPath, err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	partPath := t.RemotePath + partialFileSuffix
+	dst, err := sftpClient.OpenFile(partPath, os.O_WRONLY|os.O_CREATE)
+	if err != nil {
+		return fmt.Errorf("unable to open remote file <%v>: %w", partPath, err)
+	}
+	defer dst.Close()
+
+	offset, err := resumeOffset(dst, info.Size())
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		log.Printf("Resuming upload of <%v> from %v bytes", t.LocalPath, offset)
+	}
+	err = copyChunks(ctx, dst, src, offset, info.Size(), progress)
+	if err != nil {
+		return fmt.Errorf("unable to upload <%v>: %w", t.LocalPath, err)
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	localSum, err := localSHA256(t.LocalPath)
+	if err != nil {
+		return err
+	}
+	remoteSum, err := RemoteSHA256(ctx, client, partPath)
+	if err != nil {
+		return err
+	}
+	if localSum != remoteSum {
+		sftpClient.Remove(partPath)
+		return fmt.Errorf("%w: local %v, remote %v", ErrChecksumMismatch, localSum, remoteSum)
+	}
+	log.Printf("Upload of <%v> verified, sha256 %v", t.LocalPath, localSum)
+	if _, ok := sftpClient.HasExtension("posix-rename@openssh.com"); ok {
+		return sftpClient.PosixRename(partPath, t.RemotePath)
+	}
+	// plain sftp rename fails if destination exists
+	sftpClient.Remove(t.RemotePath)
+	return sftpClient.Rename(partPath, t.RemotePath)
+}
+
+func download(ctx context.Context, client *ssh.Client, sftpClient *sftp.Client, t Transfer, progress ProgressFunc) error {
+	src, err := sftpClient.Open(t.RemotePath)
+	if err != nil {
+		return fmt.Errorf("unable to open remote file <%v>: %w", t.RemotePath, err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	partPath := t.LocalPath + partialFileSuffix
+	dst, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open local file <%v>: %w", partPath, err)
+	}
+	defer dst.Close()
+
+	offset, err := resumeOffset(dst, info.Size())
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		log.Printf("Resuming download of <%v> from %v bytes", t.RemotePath, offset)
+	}
+	err = copyChunks(ctx, dst, src, offset, info.Size(), progress)
+	if err != nil {
+		return fmt.Errorf("unable to download <%v>: %w", t.RemotePath, err)
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	remoteSum, err := RemoteSHA256(ctx, client, t.RemotePath)
+	if err != nil {
+		return err
+	}
+	localSum, err := localSHA256(partPath)
+	if err != nil {
+		return err
+	}
+	if localSum != remoteSum {
+		os.Remove(partPath)
+		return fmt.Errorf("%w: remote %v, local %v", ErrChecksumMismatch, remoteSum, localSum)
+	}
+	log.Printf("Download of <%v> verified, sha256 %v", t.RemotePath, localSum)
+	return os.Rename(partPath, t.LocalPath)
+}
+
+type seekWriterStater interface {
+	io.WriteSeeker
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+}
+
+// returns size of partial file, partial file bigger than the source is started from scratch
+func resumeOffset(dst seekWriterStater, total int64) (int64, error) {
+	info, err := dst.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offset := info.Size()
+	if offset > total {
+		if err = dst.Truncate(0); err != nil {
+			return 0, err
+		}
+		offset = 0
+	}
+	_, err = dst.Seek(offset, io.SeekStart)
+	return offset, err
+}
+
+// copies src from offset to the end, ctx is checked between chunks
+func copyChunks(ctx context.Context, dst io.Writer, src io.ReadSeeker, offset, total int64, progress ProgressFunc) error {
+	_, err := src.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	done := offset
+	if progress != nil {
+		progress(done, total)
+	}
+	buf := make([]byte, transferChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, readErr := io.ReadFull(src, buf)
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return err
+			}
+			done += int64(n)
+			if progress != nil {
+				progress(done, total)
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+func localSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to hash <%v>: %w", p, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// computes sha256 of remote file with sha256sum
+func RemoteSHA256(ctx context.Context, client *ssh.Client, p string) (string, error) {
+	var out strings.Builder
+	_, err := Exec(ctx, client, "sha256sum -- "+ShellQuote(p), &out, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash remote file <%v>: %w", p, err)
+	}
+	fields := strings.Fields(out.String())
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("unexpected sha256sum output: %q", out.String())
+	}
+	return fields[0], nil
+}
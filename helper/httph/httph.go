@@ -1,8 +1,7 @@
 package httph
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -10,9 +9,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/KiraCore/kensho/helper/cometrpc"
+	"github.com/KiraCore/kensho/helper/interxclient"
 	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
 	sekaiendpoint "github.com/KiraCore/kensho/types/endpoint/sekai"
-	shidaiendpoint "github.com/KiraCore/kensho/types/endpoint/shidai"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -37,63 +37,39 @@ func MakeHttpRequest(url, method string) ([]byte, error) {
 	return body, nil
 }
 
-func GetInterxStatus(nodeIP, interxPort string) (*interxendpoint.Status, error) {
-	url := fmt.Sprintf("http://%v:%v/api/status", nodeIP, interxPort)
-	b, err := MakeHttpRequest(url, "GET")
+// Get fetches url with ctx, non-2xx responses are returned as *StatusError
+// and bodies bigger than MaxResponseSize are rejected
+func Get(ctx context.Context, httpClient *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	var info *interxendpoint.Status
-	err = json.Unmarshal(b, &info)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	return info, nil
-}
+	defer resp.Body.Close()
 
-func GetSekaiStatus(nodeIP, port string) (*sekaiendpoint.Status, error) {
-	url := fmt.Sprintf("http://%v:%v/status", nodeIP, port)
-	b, err := MakeHttpRequest(url, "GET")
+	body, err := readLimited(resp.Body, MaxResponseSize)
 	if err != nil {
 		return nil, err
 	}
-	var info *sekaiendpoint.Status
-	err = json.Unmarshal(b, &info)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: body}
 	}
-	return info, nil
+	return body, nil
 }
 
-func GetShidaiStatus(sshClient *ssh.Client, shidaiPort int) (shidaiendpoint.Status, error) {
-	valid := ValidatePortRange(strconv.Itoa(shidaiPort))
-	if !valid {
-		return shidaiendpoint.Status{}, fmt.Errorf("<%v> is not valid", shidaiPort)
-	}
-	o, err := ExecHttpRequestBySSHTunnel(sshClient, fmt.Sprintf("http://localhost:%v/status", shidaiPort), "GET", nil)
-	if err != nil {
-		return shidaiendpoint.Status{}, err
-	}
-	var data shidaiendpoint.Status
-	err = json.Unmarshal(o, &data)
-	if err != nil {
-		return shidaiendpoint.Status{}, err
-	}
-	return data, err
+func GetInterxStatus(nodeIP, interxPort string) (*interxendpoint.Status, error) {
+	return interxclient.New(fmt.Sprintf("http://%v:%v", nodeIP, interxPort), nil).Status(context.Background())
+}
+
+func GetSekaiStatus(nodeIP, port string) (*sekaiendpoint.Status, error) {
+	return cometrpc.New(fmt.Sprintf("http://%v:%v", nodeIP, port), nil, nil).Status(context.Background())
 }
 
 func GetSekaiABCI_Info(nodeIP, port string) (*sekaiendpoint.ABCI_Info, error) {
-	url := fmt.Sprintf("http://%v:%v/abci_info", nodeIP, port)
-	b, err := MakeHttpRequest(url, "GET")
-	if err != nil {
-		return nil, err
-	}
-	var data sekaiendpoint.ABCI_Info
-	err = json.Unmarshal(b, &data)
-	if err != nil {
-		return nil, err
-	}
-	return &data, nil
+	return cometrpc.New(fmt.Sprintf("http://%v:%v", nodeIP, port), nil, nil).ABCIInfo(context.Background())
 }
 
 func GetBinariesVersionsFromTrustedNode(trustedIP, sekaiRPC_Port, interxPort string) (sekaiVersion, interxVersion string, err error) {
@@ -113,23 +89,6 @@ func GetBinariesVersionsFromTrustedNode(trustedIP, sekaiRPC_Port, interxPort str
 	return sekaiVersion, interxVersion, nil
 }
 
-func GetValidatorStatus(sshClient *ssh.Client, shidaiPort int) (*shidaiendpoint.Validator, error) {
-	valid := ValidatePortRange(strconv.Itoa(shidaiPort))
-	if !valid {
-		return nil, fmt.Errorf("<%v> is not valid", shidaiPort)
-	}
-	o, err := ExecHttpRequestBySSHTunnel(sshClient, fmt.Sprintf("http://localhost:%v/validator", shidaiPort), "GET", nil)
-	if err != nil {
-		return nil, err
-	}
-	var data shidaiendpoint.Validator
-	err = json.Unmarshal(o, &data)
-	if err != nil {
-		return nil, err
-	}
-	return &data, err
-}
-
 func ValidatePortRange(portStr string) bool {
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
@@ -146,213 +105,9 @@ func ValidateIP(input string) bool {
 	return ipCheck != nil
 }
 
+// sends request through pooled ssh tunnel with DefaultRequestTimeout deadline
 func ExecHttpRequestBySSHTunnel(sshClient *ssh.Client, address, method string, payload []byte) ([]byte, error) {
-	log.Printf("requesting <%v>\nPayload: %+v", address, string(payload))
-	dialer := func(network, addr string) (net.Conn, error) {
-		conn, err := sshClient.Dial(network, addr)
-		if err != nil {
-			log.Printf("Failed to establish SSH tunnel: %v", err)
-		}
-		return conn, err
-	}
-
-	httpTransport := &http.Transport{
-		Dial: dialer,
-	}
-
-	httpClient := &http.Client{
-		Transport: httpTransport,
-	}
-
-	var req *http.Request
-	var err error
-
-	if len(payload) == 0 {
-		req, err = http.NewRequest(method, address, nil)
-	} else {
-		req, err = http.NewRequest(method, address, bytes.NewBuffer(payload))
-	}
-	if err != nil {
-		log.Printf("Failed to create HTTP request: %v", err)
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		log.Printf("Failed to send HTTP request: %v", err)
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		log.Printf("Non-2xx response received: %d %s", resp.StatusCode, string(bodyBytes))
-		return nil, fmt.Errorf("HTTP request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	out, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return out, nil
-}
-
-func CreateTunnelForSSEConnection(sshClient *ssh.Client, address string) (*http.Response, error) {
-	dialer := func(network, addr string) (net.Conn, error) {
-		return sshClient.Dial(network, addr)
-	}
-
-	httpTransport := &http.Transport{
-		Dial: dialer,
-	}
-
-	httpClient := &http.Client{
-		Transport: httpTransport,
-	}
-
-	req, err := http.NewRequest("GET", address, nil)
-	if err != nil {
-		log.Printf("Failed to create HTTP request: %v", err)
-		return nil, err
-	}
-	req.Header.Set("Accept", "text/event-stream")
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		log.Printf("Failed to send HTTP request: %v", err)
-		return nil, err
-
-	}
-
-	return resp, nil
-}
-
-// TODO:
-type Dashboard struct {
-	RoleIDs []string `json:"role_ids"`
-
-	Date                string `json:"date"`
-	ValidatorStatus     string `json:"val_status"`
-	Blocks              string `json:"blocks"`
-	Top                 string `json:"top"`
-	Streak              string `json:"streak"`
-	Mischance           string `json:"mischance"`
-	MischanceConfidence string `json:"mischance_confidence"`
-	StartHeight         string `json:"start_height"`
-	LastProducedBlock   string `json:"last_present_block"`
-	ProducedBlocks      string `json:"produced_blocks_counter"`
-	Moniker             string `json:"moniker"`
-	ValidatorAddress    string `json:"address"`
-	ChainID             string `json:"chain_id"`
-	NodeID              string `json:"node_id"`
-	GenesisChecksum     string `json:"genesis_checksum"`
-
-	ActiveValidators   int `json:"active_validators"`
-	PausedValidators   int `json:"paused_validators"`
-	InactiveValidators int `json:"inactive_validators"`
-	JailedValidators   int `json:"jailed_validatore"`
-	WaitingValidators  int `json:"waiting_validators"`
-
-	SeatClaimAvailable bool `json:"seat_claim_available"`
-	Waiting            bool `json:"seat_claim_pending"`
-	CatchingUp         bool `json:"catching_up"`
-}
-
-func GetDashboardInfo(sshClient *ssh.Client, shidaiPort int) (*Dashboard, error) {
-	url := fmt.Sprintf("http://localhost:%v/dashboard", shidaiPort)
-	o, err := ExecHttpRequestBySSHTunnel(sshClient, url, "GET", nil)
-	if err != nil {
-		return nil, fmt.Errorf("ERROR getting request from <%v>, reason: %w", url, err)
-	}
-	var data *Dashboard
-	err = json.Unmarshal(o, &data)
-	if err != nil {
-		return nil, fmt.Errorf("ERROR when unmarshaling <%v>\nReason: %w", string(o), err)
-	}
-	return data, nil
-}
-
-type ConfigRequest struct {
-	Type     string `json:"type"`
-	TomlData string `json:"toml_data"`
-}
-
-const (
-	configTomlType = "config_toml"
-	appTomlType    = "app_toml"
-)
-
-// returns app.toml as string from remote host
-func GetAppTomlConfig(sshClient *ssh.Client, shidaiPort int) (string, error) {
-	url := fmt.Sprintf("http://localhost:%v/config", shidaiPort)
-	payload := ConfigRequest{
-		Type: appTomlType,
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
-	}
-
-	out, err := ExecHttpRequestBySSHTunnel(sshClient, url, http.MethodPost, payloadBytes)
-	if err != nil {
-		return "", fmt.Errorf("error when getting toml config from remote\nOut: %v\nerr: %w", string(out), err)
-	}
-	return string(out), nil
-}
-
-// returns config.toml as string from remote host
-func GetConfigTomlConfig(sshClient *ssh.Client, shidaiPort int) (string, error) {
-	url := fmt.Sprintf("http://localhost:%v/config", shidaiPort)
-	payload := ConfigRequest{
-		Type: configTomlType,
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
-	}
-
-	out, err := ExecHttpRequestBySSHTunnel(sshClient, url, http.MethodPost, payloadBytes)
-	if err != nil {
-		return "", fmt.Errorf("error when getting toml config from remote\nOut: %v\nerr: %w", string(out), err)
-	}
-	return string(out), nil
-}
-
-// put
-func SetConfigTomlConfig(sshClient *ssh.Client, config string, shidaiPort int) error {
-	url := fmt.Sprintf("http://localhost:%v/config", shidaiPort)
-	payload := ConfigRequest{
-		Type:     configTomlType,
-		TomlData: config,
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	out, err := ExecHttpRequestBySSHTunnel(sshClient, url, http.MethodPut, payloadBytes)
-	if err != nil {
-		return fmt.Errorf("error when setting config.toml from remote\nOut: %v\nerr: %w", string(out), err)
-	}
-	return nil
-}
-func SetAppTomlConfig(sshClient *ssh.Client, config string, shidaiPort int) error {
-	url := fmt.Sprintf("http://localhost:%v/config", shidaiPort)
-	payload := ConfigRequest{
-		Type:     appTomlType,
-		TomlData: config,
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	out, err := ExecHttpRequestBySSHTunnel(sshClient, url, http.MethodPut, payloadBytes)
-	if err != nil {
-		return fmt.Errorf("error when setting config.toml from remote\nOut: %v\nerr: %w", string(out), err)
-	}
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+	return ExecHttpRequestBySSHTunnelWithContext(ctx, sshClient, address, method, payload)
 }
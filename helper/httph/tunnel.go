@@ -0,0 +1,159 @@
+package httph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// deadline of requests made without context
+	DefaultRequestTimeout = time.Minute
+
+	// bodies bigger than this are rejected instead of being read into memory
+	MaxResponseSize = 16 << 20
+
+	tunnelMaxAttempts = 3
+	tunnelRetryDelay  = 500 * time.Millisecond
+)
+
+var ErrResponseTooLarge = errors.New("response body exceeds size limit")
+
+// returned for non-2xx responses, body is kept for API specific error parsing
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP request failed with status code %d: %s", e.StatusCode, string(e.Body))
+}
+
+// one http client per ssh client, connections through the tunnel are kept alive and reused between requests.
+// Entry is dropped when ssh connection is closed, reconnected host gets new client
+var tunnels = struct {
+	mu      sync.Mutex
+	clients map[*ssh.Client]*http.Client
+}{clients: map[*ssh.Client]*http.Client{}}
+
+// returns pooled http client which dials every connection through sshClient.
+// Client has no timeout, deadlines are set per request with context
+func TunnelClient(sshClient *ssh.Client) *http.Client {
+	tunnels.mu.Lock()
+	defer tunnels.mu.Unlock()
+	if c, ok := tunnels.clients[sshClient]; ok {
+		return c
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := sshClient.DialContext(ctx, network, addr)
+			if err != nil {
+				log.Printf("Failed to establish SSH tunnel to <%v>: %v", addr, err)
+			}
+			return conn, err
+		},
+		MaxIdleConns:        16,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	c := &http.Client{Transport: transport}
+	tunnels.clients[sshClient] = c
+
+	go func() {
+		sshClient.Wait()
+		transport.CloseIdleConnections()
+		tunnels.mu.Lock()
+		delete(tunnels.clients, sshClient)
+		tunnels.mu.Unlock()
+	}()
+	return c
+}
+
+// sends request through pooled ssh tunnel and returns body of 2xx response.
+// Idempotent requests are retried when connection fails, ctx deadline covers all attempts
+func ExecHttpRequestBySSHTunnelWithContext(ctx context.Context, sshClient *ssh.Client, address, method string, payload []byte) ([]byte, error) {
+	// payload is not logged, join payload contains the mnemonic
+	log.Printf("requesting <%v>, payload %v bytes", address, len(payload))
+	httpClient := TunnelClient(sshClient)
+
+	var err error
+	for attempt := 1; attempt <= tunnelMaxAttempts; attempt++ {
+		var out []byte
+		var retry bool
+		out, retry, err = doTunnelRequest(ctx, httpClient, address, method, payload)
+		if err == nil {
+			return out, nil
+		}
+		if !retry || !isIdempotent(method) || ctx.Err() != nil || attempt == tunnelMaxAttempts {
+			break
+		}
+		log.Printf("Request to <%v> failed (attempt %v/%v), retrying: %v", address, attempt, tunnelMaxAttempts, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(tunnelRetryDelay * time.Duration(attempt)):
+		}
+	}
+	return nil, err
+}
+
+// single attempt, retry is set for connection failures and temporarily unavailable service
+func doTunnelRequest(ctx context.Context, httpClient *http.Client, address, method string, payload []byte) (out []byte, retry bool, err error) {
+	var body io.Reader
+	if len(payload) > 0 {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, address, body)
+	if err != nil {
+		log.Printf("Failed to create HTTP request: %v", err)
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		log.Printf("Failed to send HTTP request: %v", err)
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	out, err = readLimited(resp.Body, MaxResponseSize)
+	if err != nil {
+		return nil, !errors.Is(err, ErrResponseTooLarge), fmt.Errorf("unable to read response from <%v>: %w", address, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Non-2xx response received: %d %s", resp.StatusCode, string(out))
+		retry = resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout
+		return nil, retry, &StatusError{StatusCode: resp.StatusCode, Body: out}
+	}
+	return out, false, nil
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	out, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(out)) > limit {
+		return nil, fmt.Errorf("%w of %v bytes", ErrResponseTooLarge, limit)
+	}
+	return out, nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package interxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
+)
+
+const (
+	// deadline of requests when ctx has none
+	DefaultTimeout = 10 * time.Second
+	// bodies bigger than this are rejected instead of being read into memory
+	maxResponseSize = 32 << 20
+)
+
+var ErrResponseTooLarge = errors.New("response body exceeds size limit")
+
+// non-2xx response of interx
+type APIError struct {
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("interx %v failed with status %d: %v", e.Path, e.StatusCode, e.Message)
+}
+
+// Client talks to interx REST API of a single node
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// baseURL is e.g. "http://1.2.3.4:11000", nil httpClient means http.DefaultClient
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+func NewForNode(ip string, port int, httpClient *http.Client) *Client {
+	return New(fmt.Sprintf("http://%v:%v", ip, port), httpClient)
+}
+
+func (c *Client) Status(ctx context.Context) (*interxendpoint.Status, error) {
+	var data interxendpoint.Status
+	if err := c.get(ctx, "/api/status", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) NetInfo(ctx context.Context) (*interxendpoint.NetInfo, error) {
+	var data interxendpoint.NetInfo
+	if err := c.get(ctx, "/api/net_info", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+type ValopersQuery struct {
+	// ACTIVE, INACTIVE, PAUSED, JAILED or empty for all
+	Status  string
+	Address string
+	Moniker string
+	Page    Page
+}
+
+func (c *Client) Valopers(ctx context.Context, q ValopersQuery) (*interxendpoint.Validators, error) {
+	params := q.Page.values()
+	setIfNotEmpty(params, "status", q.Status)
+	setIfNotEmpty(params, "address", q.Address)
+	setIfNotEmpty(params, "moniker", q.Moniker)
+	if len(params) == 0 {
+		params.Set("all", "true")
+	}
+	var data interxendpoint.Validators
+	if err := c.get(ctx, "/api/valopers", params, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) Balances(ctx context.Context, address string, page Page) (*interxendpoint.Balances, error) {
+	var data interxendpoint.Balances
+	if err := c.get(ctx, "/api/kira/balances/"+url.PathEscape(address), page.values(), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+type TransactionsQuery struct {
+	Address string
+	// inbound, outbound or empty for both
+	Direction string
+	// 1-based page of PageSize transactions
+	Page     int
+	PageSize int
+}
+
+func (c *Client) Transactions(ctx context.Context, q TransactionsQuery) (*interxendpoint.Transactions, error) {
+	params := url.Values{}
+	params.Set("address", q.Address)
+	setIfNotEmpty(params, "direction", q.Direction)
+	if q.Page > 0 {
+		params.Set("page", strconv.Itoa(q.Page))
+	}
+	if q.PageSize > 0 {
+		params.Set("page_size", strconv.Itoa(q.PageSize))
+	}
+	var data interxendpoint.Transactions
+	if err := c.get(ctx, "/api/transactions", params, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) Proposals(ctx context.Context, page Page) (*interxendpoint.Proposals, error) {
+	var data interxendpoint.Proposals
+	if err := c.get(ctx, "/api/kira/gov/proposals", page.values(), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) IdentityRecords(ctx context.Context, address string) (*interxendpoint.IdentityRecords, error) {
+	var data interxendpoint.IdentityRecords
+	if err := c.get(ctx, "/api/kira/gov/identity_records/"+url.PathEscape(address), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// returns faucet address and its balances
+func (c *Client) FaucetInfo(ctx context.Context) (*interxendpoint.FaucetInfo, error) {
+	var data interxendpoint.FaucetInfo
+	if err := c.get(ctx, "/api/faucet", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// requests faucet to send token to address
+func (c *Client) FaucetClaim(ctx context.Context, address, token string) (*interxendpoint.FaucetClaim, error) {
+	params := url.Values{}
+	params.Set("claim", address)
+	params.Set("token", token)
+	var data interxendpoint.FaucetClaim
+	if err := c.get(ctx, "/api/faucet", params, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values, data any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	address := c.baseURL + path
+	if len(params) > 0 {
+		address += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return fmt.Errorf("unable to read <%v> response: %w", path, err)
+	}
+	if len(b) > maxResponseSize {
+		return fmt.Errorf("<%v>: %w", path, ErrResponseTooLarge)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{Path: path, StatusCode: resp.StatusCode, Message: errorMessage(b)}
+	}
+	if err = json.Unmarshal(b, data); err != nil {
+		return fmt.Errorf("unable to decode <%v> response: %w", path, err)
+	}
+	return nil
+}
+
+// interx errors are json with message field, anything else is returned as is
+func errorMessage(body []byte) string {
+	var e struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &e) == nil && e.Message != "" {
+		return e.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func setIfNotEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
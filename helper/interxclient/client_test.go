@@ -0,0 +1,282 @@
+package interxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
+)
+
+// starts interx stub, handlers are keyed by path, every request url is recorded
+func newTestClient(t *testing.T, handlers map[string]http.HandlerFunc) (*Client, *[]*url.URL) {
+	t.Helper()
+	var requests []*url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		if h, ok := handlers[r.URL.Path]; ok {
+			h(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", srv.Client()), &requests
+}
+
+func writeJSON(t *testing.T, data any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestStatusAndNetInfo(t *testing.T) {
+	c, requests := newTestClient(t, map[string]http.HandlerFunc{
+		"/api/status": func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"id":"abc","node_info":{"network":"testnet-1","moniker":"kira"},"sync_info":{"latest_block_height":"1234"}}`)
+		},
+		"/api/net_info": func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"listening":true,"n_peers":2,"peers":[{"remote_ip":"1.1.1.1"},{"remote_ip":"2.2.2.2"}]}`)
+		},
+	})
+	ctx := context.Background()
+
+	status, err := c.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ID != "abc" || status.NodeInfo.Network != "testnet-1" || status.SyncInfo.LatestBlockHeight != "1234" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	netInfo, err := c.NetInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !netInfo.Listening || netInfo.NPeers != 2 || len(netInfo.Peers) != 2 || netInfo.Peers[1].RemoteIP != "2.2.2.2" {
+		t.Fatalf("unexpected net info %+v", netInfo)
+	}
+	if len(*requests) != 2 || (*requests)[0].RawQuery != "" {
+		t.Fatalf("unexpected requests %v", *requests)
+	}
+}
+
+func TestValopersQuery(t *testing.T) {
+	c, requests := newTestClient(t, map[string]http.HandlerFunc{
+		"/api/valopers": writeJSON(t, interxendpoint.Validators{Validators: []interxendpoint.Validator{{Moniker: "kira", Valkey: "kiravaloper1"}}}),
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query ValopersQuery
+		want  url.Values
+	}{
+		{"all", ValopersQuery{}, url.Values{"all": {"true"}}},
+		{"filtered", ValopersQuery{Status: "ACTIVE", Moniker: "kira", Page: Page{Offset: 10, Limit: 5, CountTotal: true}},
+			url.Values{"status": {"ACTIVE"}, "moniker": {"kira"}, "offset": {"10"}, "limit": {"5"}, "count_total": {"true"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := c.Valopers(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data.Validators) != 1 || data.Validators[0].Valkey != "kiravaloper1" {
+				t.Fatalf("unexpected validators %+v", data.Validators)
+			}
+			if got := (*requests)[len(*requests)-1].Query(); got.Encode() != tt.want.Encode() {
+				t.Fatalf("query %v, want %v", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestBalancesEscapesAddress(t *testing.T) {
+	c, requests := newTestClient(t, map[string]http.HandlerFunc{
+		"/api/kira/balances/kira1abc": writeJSON(t, interxendpoint.Balances{Balances: []interxendpoint.Coin{{Denom: "ukex", Amount: "100"}}}),
+	})
+
+	data, err := c.Balances(context.Background(), "kira1abc", Page{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Balances) != 1 || data.Balances[0].Amount != "100" {
+		t.Fatalf("unexpected balances %+v", data)
+	}
+	if q := (*requests)[0].Query(); q.Get("limit") != "1" || q.Has("offset") {
+		t.Fatalf("unexpected query %v", q)
+	}
+
+	_, err = c.Balances(context.Background(), "../status", Page{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Path != "/api/kira/balances/..%2Fstatus" {
+		t.Fatalf("address is not escaped: %v", err)
+	}
+}
+
+// serves n coins with offset and limit, like interx does
+func paginatedBalances(t *testing.T, n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var coins []interxendpoint.Coin
+		for i := offset; i < n && i < offset+limit; i++ {
+			coins = append(coins, interxendpoint.Coin{Denom: fmt.Sprintf("c%v", i), Amount: "1"})
+		}
+		writeJSON(t, interxendpoint.Balances{Balances: coins})(w, r)
+	}
+}
+
+func TestAllBalances(t *testing.T) {
+	for _, n := range []int{0, 1, DefaultPageSize, DefaultPageSize + 1, 3*DefaultPageSize - 1} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			c, requests := newTestClient(t, map[string]http.HandlerFunc{"/api/kira/balances/kira1": paginatedBalances(t, n)})
+			coins, err := c.AllBalances(context.Background(), "kira1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(coins) != n {
+				t.Fatalf("got %v coins, want %v", len(coins), n)
+			}
+			for i, coin := range coins {
+				if coin.Denom != fmt.Sprintf("c%v", i) {
+					t.Fatalf("coin %v is %v", i, coin.Denom)
+				}
+			}
+			if want := n/DefaultPageSize + 1; len(*requests) != want {
+				t.Fatalf("made %v requests, want %v", len(*requests), want)
+			}
+		})
+	}
+}
+
+func TestFetchAllIgnoredPagination(t *testing.T) {
+	t.Run("repeated page", func(t *testing.T) {
+		// server ignores offset and always returns the same full page
+		c, requests := newTestClient(t, map[string]http.HandlerFunc{
+			"/api/kira/balances/kira1": func(w http.ResponseWriter, r *http.Request) {
+				r.URL.RawQuery = "limit=" + strconv.Itoa(DefaultPageSize)
+				paginatedBalances(t, 1000)(w, r)
+			},
+		})
+		coins, err := c.AllBalances(context.Background(), "kira1")
+		if !errors.Is(err, ErrPaginationIgnored) {
+			t.Fatalf("expected ErrPaginationIgnored, got %v", err)
+		}
+		if len(coins) != DefaultPageSize || len(*requests) != 2 {
+			t.Fatalf("got %v coins after %v requests", len(coins), len(*requests))
+		}
+	})
+
+	t.Run("page limit", func(t *testing.T) {
+		calls := 0
+		items, err := FetchAll(context.Background(), 1, func(_ context.Context, p Page) ([]int, error) {
+			calls++
+			return []int{p.Offset}, nil
+		})
+		if !errors.Is(err, ErrPaginationIgnored) || calls != MaxPages || len(items) != MaxPages {
+			t.Fatalf("err %v after %v calls with %v items", err, calls, len(items))
+		}
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		fail := errors.New("boom")
+		items, err := FetchAll(context.Background(), 2, func(_ context.Context, p Page) ([]int, error) {
+			if p.Offset > 0 {
+				return nil, fail
+			}
+			return []int{1, 2}, nil
+		})
+		if !errors.Is(err, fail) || len(items) != 2 {
+			t.Fatalf("got %v, %v", items, err)
+		}
+	})
+}
+
+func TestAllTransactions(t *testing.T) {
+	c, requests := newTestClient(t, map[string]http.HandlerFunc{
+		"/api/transactions": func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+			data := interxendpoint.Transactions{TotalCount: 5}
+			for i := (page - 1) * size; i < 5 && i < page*size; i++ {
+				data.Transactions = append(data.Transactions, interxendpoint.Transaction{Hash: strconv.Itoa(i)})
+			}
+			writeJSON(t, data)(w, r)
+		},
+	})
+	txs, err := c.AllTransactions(context.Background(), TransactionsQuery{Address: "kira1", PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 5 || txs[4].Hash != "4" || len(*requests) != 3 {
+		t.Fatalf("got %v transactions after %v requests", len(txs), len(*requests))
+	}
+	if q := (*requests)[0].Query(); q.Get("address") != "kira1" || q.Get("page") != "1" {
+		t.Fatalf("unexpected query %v", q)
+	}
+
+	t.Run("server ignores page", func(t *testing.T) {
+		c, requests := newTestClient(t, map[string]http.HandlerFunc{
+			"/api/transactions": writeJSON(t, interxendpoint.Transactions{
+				TotalCount:   100,
+				Transactions: []interxendpoint.Transaction{{Hash: "0"}, {Hash: "1"}},
+			}),
+		})
+		txs, err := c.AllTransactions(context.Background(), TransactionsQuery{Address: "kira1", PageSize: 2})
+		if !errors.Is(err, ErrPaginationIgnored) {
+			t.Fatalf("expected ErrPaginationIgnored, got %v", err)
+		}
+		if len(txs) != 2 || len(*requests) != 2 {
+			t.Fatalf("got %v transactions after %v requests", len(txs), len(*requests))
+		}
+	})
+}
+
+func TestErrors(t *testing.T) {
+	c, _ := newTestClient(t, map[string]http.HandlerFunc{
+		"/api/status": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"node is syncing"}`)
+		},
+		"/api/net_info": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "bad gateway\n")
+		},
+		"/api/faucet": func(w http.ResponseWriter, _ *http.Request) { fmt.Fprint(w, "{not json") },
+		"/api/kira/gov/proposals": func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		},
+	})
+	ctx := context.Background()
+
+	var apiErr *APIError
+	_, err := c.Status(ctx)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 || apiErr.Message != "node is syncing" || apiErr.Path != "/api/status" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, err = c.NetInfo(ctx)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 502 || apiErr.Message != "bad gateway" {
+		t.Fatalf("plain text error body is not kept: %v", err)
+	}
+	if _, err = c.FaucetInfo(ctx); err == nil || !strings.Contains(err.Error(), "unable to decode") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err = c.Proposals(timeoutCtx, Page{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package interxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
+)
+
+const (
+	// default page size of All* helpers
+	DefaultPageSize = 100
+	// All* helpers give up after this many pages, protects against servers which ignore pagination
+	MaxPages = 1000
+)
+
+var ErrPaginationIgnored = errors.New("server ignores pagination")
+
+// offset based pagination of cosmos style endpoints, zero value requests first page with interx default limit
+type Page struct {
+	Offset     int
+	Limit      int
+	CountTotal bool
+}
+
+func (p Page) values() url.Values {
+	params := url.Values{}
+	if p.Offset > 0 {
+		params.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		params.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.CountTotal {
+		params.Set("count_total", "true")
+	}
+	return params
+}
+
+// FetchAll requests pages of pageSize items until a page is shorter than pageSize.
+// Page equal to the previous one or more than MaxPages pages return ErrPaginationIgnored
+func FetchAll[T any](ctx context.Context, pageSize int, fetch func(ctx context.Context, page Page) ([]T, error)) ([]T, error) {
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	var all, previous []T
+	for i := 0; i < MaxPages; i++ {
+		items, err := fetch(ctx, Page{Offset: i * pageSize, Limit: pageSize})
+		if err != nil {
+			return all, err
+		}
+		if i > 0 && len(items) > 0 && reflect.DeepEqual(items, previous) {
+			return all, fmt.Errorf("%w: page at offset %v repeats previous page", ErrPaginationIgnored, i*pageSize)
+		}
+		all = append(all, items...)
+		if len(items) < pageSize {
+			return all, nil
+		}
+		previous = items
+	}
+	return all, fmt.Errorf("%w: more than %v pages of %v items", ErrPaginationIgnored, MaxPages, pageSize)
+}
+
+func (c *Client) AllValopers(ctx context.Context, q ValopersQuery) ([]interxendpoint.Validator, error) {
+	return FetchAll(ctx, q.Page.Limit, func(ctx context.Context, page Page) ([]interxendpoint.Validator, error) {
+		q.Page = page
+		data, err := c.Valopers(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return data.Validators, nil
+	})
+}
+
+func (c *Client) AllBalances(ctx context.Context, address string) ([]interxendpoint.Coin, error) {
+	return FetchAll(ctx, DefaultPageSize, func(ctx context.Context, page Page) ([]interxendpoint.Coin, error) {
+		data, err := c.Balances(ctx, address, page)
+		if err != nil {
+			return nil, err
+		}
+		return data.Balances, nil
+	})
+}
+
+func (c *Client) AllProposals(ctx context.Context) ([]interxendpoint.Proposal, error) {
+	return FetchAll(ctx, DefaultPageSize, func(ctx context.Context, page Page) ([]interxendpoint.Proposal, error) {
+		data, err := c.Proposals(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		return data.Proposals, nil
+	})
+}
+
+// AllTransactions walks page based transactions endpoint until TotalCount transactions are collected
+func (c *Client) AllTransactions(ctx context.Context, q TransactionsQuery) ([]interxendpoint.Transaction, error) {
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	var all, previous []interxendpoint.Transaction
+	for q.Page = 1; q.Page <= MaxPages; q.Page++ {
+		data, err := c.Transactions(ctx, q)
+		if err != nil {
+			return all, err
+		}
+		if q.Page > 1 && len(data.Transactions) > 0 && reflect.DeepEqual(data.Transactions, previous) {
+			return all, fmt.Errorf("%w: page %v repeats previous page", ErrPaginationIgnored, q.Page)
+		}
+		all = append(all, data.Transactions...)
+		if len(data.Transactions) < q.PageSize || len(all) >= data.TotalCount {
+			return all, nil
+		}
+		previous = data.Transactions
+	}
+	return all, fmt.Errorf("%w: more than %v pages of %v transactions", ErrPaginationIgnored, MaxPages, q.PageSize)
+}
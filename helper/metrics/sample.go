@@ -0,0 +1,57 @@
+package metrics
+
+import "time"
+
+type Metric string
+
+const (
+	BlockHeight    Metric = "block_height"
+	Peers          Metric = "peers"
+	Streak         Metric = "streak"
+	Mischance      Metric = "mischance"
+	ProducedBlocks Metric = "produced_blocks"
+	// ssh round trip time in milliseconds
+	SSHRTT Metric = "ssh_rtt_ms"
+)
+
+var Metrics = []Metric{BlockHeight, Peers, Streak, Mischance, ProducedBlocks, SSHRTT}
+
+func (m Metric) Title() string {
+	switch m {
+	case BlockHeight:
+		return "Block height"
+	case Peers:
+		return "Peers"
+	case Streak:
+		return "Streak"
+	case Mischance:
+		return "Mischance"
+	case ProducedBlocks:
+		return "Produced blocks"
+	case SSHRTT:
+		return "SSH RTT (ms)"
+	}
+	return string(m)
+}
+
+// Sample holds values collected from a host at one moment, metrics which could not be collected are missing
+type Sample struct {
+	Time   time.Time          `json:"t"`
+	Values map[Metric]float64 `json:"v"`
+	// number of collected samples averaged into this one, zero for collected sample
+	Count int `json:"n,omitempty"`
+}
+
+// weight of sample in averages
+func (s Sample) weight() int {
+	return max(s.Count, 1)
+}
+
+func NewSample(t time.Time) Sample {
+	return Sample{Time: t, Values: map[Metric]float64{}}
+}
+
+type Point struct {
+	Time  time.Time
+	Value float64
+}
@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// host file is compacted after this many appends
+const compactEvery = 360
+
+// Tier keeps samples younger than Age at Resolution, zero resolution keeps every sample
+type Tier struct {
+	Age        time.Duration
+	Resolution time.Duration
+}
+
+// Retention tiers ordered by Age, samples older than the last tier are dropped
+type Retention []Tier
+
+// full resolution for a day, 5 minute averages for a week and hourly averages for a month
+var DefaultRetention = Retention{
+	{Age: 24 * time.Hour},
+	{Age: 7 * 24 * time.Hour, Resolution: 5 * time.Minute},
+	{Age: 30 * 24 * time.Hour, Resolution: time.Hour},
+}
+
+// Store keeps samples of every host in its own json lines file inside dir
+type Store struct {
+	dir       string
+	retention Retention
+
+	mu sync.Mutex
+	// appends since last compaction per host
+	appends map[string]int
+}
+
+func NewStore(dir string, retention Retention) *Store {
+	return &Store{dir: dir, retention: retention, appends: map[string]int{}}
+}
+
+func (s *Store) path(host string) string {
+	return filepath.Join(s.dir, url.PathEscape(host)+".jsonl")
+}
+
+func (s *Store) Append(host string, sample Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("unable to create metrics folder: %w", err)
+	}
+	b, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path(host), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open metrics of <%v>: %w", host, err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write metrics of <%v>: %w", host, err)
+	}
+
+	// first append compacts what was left from previous runs
+	count, seen := s.appends[host]
+	s.appends[host] = count + 1
+	if !seen || count+1 >= compactEvery {
+		s.appends[host] = 0
+		if err = s.compact(host, time.Now()); err != nil {
+			log.Printf("Unable to compact metrics of <%v>: %v", host, err)
+		}
+	}
+	return nil
+}
+
+// Query returns values of metric recorded for host since given time, oldest first
+func (s *Store) Query(host string, metric Metric, since time.Time) ([]Point, error) {
+	s.mu.Lock()
+	samples, err := s.read(host)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	points := []Point{}
+	for _, sample := range samples {
+		v, ok := sample.Values[metric]
+		if ok && !sample.Time.Before(since) {
+			points = append(points, Point{Time: sample.Time, Value: v})
+		}
+	}
+	return points, nil
+}
+
+// must be called with s.mu held, missing file means no samples
+func (s *Store) read(host string) ([]Sample, error) {
+	f, err := os.Open(s.path(host))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read metrics of <%v>: %w", host, err)
+	}
+	defer f.Close()
+
+	samples := []Sample{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Buffer(nil, 1<<20); scanner.Scan(); {
+		var sample Sample
+		// line cut by crash is skipped, it gets dropped on next compaction
+		if json.Unmarshal(scanner.Bytes(), &sample) != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read metrics of <%v>: %w", host, err)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, nil
+}
+
+// must be called with s.mu held, file is replaced atomically
+func (s *Store) compact(host string, now time.Time) error {
+	samples, err := s.read(host)
+	if err != nil {
+		return err
+	}
+	samples = s.retention.Apply(samples, now)
+
+	tmp := s.path(host) + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, sample := range samples {
+		if err = enc.Encode(sample); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, s.path(host))
+}
+
+// Apply drops samples older than retention and averages samples of every tier into its resolution buckets.
+// Samples have to be sorted by time.
+func (r Retention) Apply(samples []Sample, now time.Time) []Sample {
+	out := []Sample{}
+	var bucket []Sample
+	var bucketStart time.Time
+	flush := func() {
+		if len(bucket) > 0 {
+			out = append(out, average(bucket))
+			bucket = nil
+		}
+	}
+	for _, sample := range samples {
+		tier, ok := r.tier(now.Sub(sample.Time))
+		if !ok {
+			continue
+		}
+		if tier.Resolution <= 0 {
+			flush()
+			out = append(out, sample)
+			continue
+		}
+		start := sample.Time.Truncate(tier.Resolution)
+		if len(bucket) > 0 && !start.Equal(bucketStart) {
+			flush()
+		}
+		bucketStart = start
+		bucket = append(bucket, sample)
+	}
+	flush()
+	return out
+}
+
+func (r Retention) tier(age time.Duration) (Tier, bool) {
+	for _, t := range r {
+		if age < t.Age {
+			return t, true
+		}
+	}
+	return Tier{}, false
+}
+
+// average of values weighted by number of samples already averaged into them, time of the last sample is kept
+func average(samples []Sample) Sample {
+	if len(samples) == 1 {
+		return samples[0]
+	}
+	out := NewSample(samples[len(samples)-1].Time)
+	weights := map[Metric]int{}
+	for _, s := range samples {
+		w := s.weight()
+		out.Count += w
+		for m, v := range s.Values {
+			out.Values[m] += v * float64(w)
+			weights[m] += w
+		}
+	}
+	for m, w := range weights {
+		out.Values[m] /= float64(w)
+	}
+	return out
+}
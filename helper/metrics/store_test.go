@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// retention with raw samples for an hour and 10 minute buckets for a day
+var testRetention = Retention{
+	{Age: time.Hour},
+	{Age: 24 * time.Hour, Resolution: 10 * time.Minute},
+}
+
+func sample(age time.Duration, values map[Metric]float64) Sample {
+	return Sample{Time: testNow.Add(-age), Values: values}
+}
+
+func TestRetentionApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []Sample
+		want    []Sample
+	}{
+		{
+			name:    "empty",
+			samples: nil,
+			want:    []Sample{},
+		},
+		{
+			name: "raw tier is kept as is",
+			samples: []Sample{
+				sample(50*time.Minute, map[Metric]float64{Peers: 1}),
+				sample(10*time.Minute, map[Metric]float64{Peers: 2}),
+			},
+			want: []Sample{
+				sample(50*time.Minute, map[Metric]float64{Peers: 1}),
+				sample(10*time.Minute, map[Metric]float64{Peers: 2}),
+			},
+		},
+		{
+			name: "older than retention is dropped",
+			samples: []Sample{
+				sample(25*time.Hour, map[Metric]float64{Peers: 9}),
+				sample(time.Minute, map[Metric]float64{Peers: 1}),
+			},
+			want: []Sample{sample(time.Minute, map[Metric]float64{Peers: 1})},
+		},
+		{
+			name: "bucket is averaged with time of the last sample",
+			samples: []Sample{
+				sample(2*time.Hour+9*time.Minute, map[Metric]float64{Peers: 1, Streak: 10}),
+				sample(2*time.Hour+5*time.Minute, map[Metric]float64{Peers: 2}),
+				sample(2*time.Hour+1*time.Minute, map[Metric]float64{Peers: 6, Streak: 20}),
+			},
+			want: []Sample{{
+				Time:   testNow.Add(-2*time.Hour - time.Minute),
+				Values: map[Metric]float64{Peers: 3, Streak: 15},
+				Count:  3,
+			}},
+		},
+		{
+			name: "averaged sample is weighted by its count",
+			samples: []Sample{
+				{Time: testNow.Add(-2*time.Hour - 9*time.Minute), Values: map[Metric]float64{Peers: 2}, Count: 3},
+				sample(2*time.Hour+1*time.Minute, map[Metric]float64{Peers: 6}),
+			},
+			want: []Sample{{
+				Time:   testNow.Add(-2*time.Hour - time.Minute),
+				Values: map[Metric]float64{Peers: 3},
+				Count:  4,
+			}},
+		},
+		{
+			name: "separate buckets",
+			samples: []Sample{
+				sample(3*time.Hour+5*time.Minute, map[Metric]float64{Peers: 1}),
+				sample(3*time.Hour+1*time.Minute, map[Metric]float64{Peers: 3}),
+				sample(2*time.Hour+5*time.Minute, map[Metric]float64{Peers: 5}),
+				sample(30*time.Minute, map[Metric]float64{Peers: 7}),
+			},
+			want: []Sample{
+				{Time: testNow.Add(-3*time.Hour - time.Minute), Values: map[Metric]float64{Peers: 2}, Count: 2},
+				sample(2*time.Hour+5*time.Minute, map[Metric]float64{Peers: 5}),
+				sample(30*time.Minute, map[Metric]float64{Peers: 7}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testRetention.Apply(tt.samples, testNow)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// compacting repeatedly while samples arrive gives the same average as compacting once
+func TestRetentionApplyRepeatedCompaction(t *testing.T) {
+	// bucket [now-2h10m, now-2h) gets a sample every minute, compacted after each one
+	var stored []Sample
+	sum := 0.0
+	for i := 0; i < 10; i++ {
+		v := float64(i * i)
+		sum += v
+		stored = append(stored, sample(2*time.Hour+time.Duration(10-i)*time.Minute, map[Metric]float64{Peers: v}))
+		stored = testRetention.Apply(stored, testNow)
+	}
+	if len(stored) != 1 || stored[0].Count != 10 {
+		t.Fatalf("expected single bucket of 10 samples, got %+v", stored)
+	}
+	if got, want := stored[0].Values[Peers], sum/10; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("average drifted to %v, want %v", got, want)
+	}
+}
+
+func TestStoreAppendQuery(t *testing.T) {
+	s := NewStore(t.TempDir(), DefaultRetention)
+	now := time.Now()
+	for i, v := range []float64{1, 2, 3} {
+		smp := NewSample(now.Add(time.Duration(i-3) * time.Minute))
+		smp.Values[BlockHeight] = v
+		if err := s.Append("user@host:22", smp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	points, err := s.Query("user@host:22", BlockHeight, now.Add(-150*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].Value != 2 || points[1].Value != 3 {
+		t.Fatalf("unexpected points %+v", points)
+	}
+	if points, err = s.Query("other", BlockHeight, time.Time{}); err != nil || len(points) != 0 {
+		t.Fatalf("unknown host: %v, %v", points, err)
+	}
+}
@@ -2,15 +2,14 @@ package networkparser
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"regexp"
 	"sync"
 	"time"
 
+	"github.com/KiraCore/kensho/helper/interxclient"
 	interxendpoint "github.com/KiraCore/kensho/types/endpoint/interx"
 )
 
@@ -168,59 +167,13 @@ func GetNetInfoFromInterx(ctx context.Context, client *http.Client, ip string, p
 
 	ctxWithTO, c := context.WithTimeout(ctx, TimeOutDelay)
 	defer c()
-	// log.Printf("Getting net_info from: %v", ip)
-	url := fmt.Sprintf("http://%v:%d/api/net_info", ip, port)
-	req, err := http.NewRequestWithContext(ctxWithTO, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var nodeInfo interxendpoint.NetInfo
-	err = json.Unmarshal(b, &nodeInfo)
-	if err != nil {
-		return nil, err
-	}
-	return &nodeInfo, nil
+	return interxclient.NewForNode(ip, port, client).NetInfo(ctxWithTO)
 }
 
 func GetStatusFromInterx(ctx context.Context, client *http.Client, ip string, port int) (*interxendpoint.Status, error) {
 	ctxWithTO, c := context.WithTimeout(ctx, TimeOutDelay)
 	defer c()
-	// log.Printf("Getting net_info from: %v", ip)
-	url := fmt.Sprintf("http://%v:%d/api/status", ip, port)
-	req, err := http.NewRequestWithContext(ctxWithTO, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var nodeStatus interxendpoint.Status
-	err = json.Unmarshal(b, &nodeStatus)
-	if err != nil {
-		return nil, err
-	}
-	return &nodeStatus, nil
+	return interxclient.NewForNode(ip, port, client).Status(ctxWithTO)
 }
 
 func extractIP(input string) (ip string, port string, err error) {
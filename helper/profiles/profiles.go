@@ -0,0 +1,282 @@
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/KiraCore/kensho/types"
+)
+
+type AuthMethod string
+
+const (
+	AuthPassword    AuthMethod = "password"
+	AuthKeyFile     AuthMethod = "key_file"
+	AuthAgent       AuthMethod = "agent"
+	AuthCertificate AuthMethod = "certificate"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is a saved ssh connection.
+// Secrets (passwords, passphrases, raw keys) are never part of the profile.
+type Profile struct {
+	Name       string     `json:"name"`
+	Host       string     `json:"host"`
+	Port       int        `json:"port"`
+	User       string     `json:"user"`
+	AuthMethod AuthMethod `json:"auth_method"`
+	KeyPath    string     `json:"key_path,omitempty"`
+	CertPath   string     `json:"cert_path,omitempty"`
+	ShidaiPort int        `json:"shidai_port"`
+	InterxPort int        `json:"interx_port"`
+	RPCPort    int        `json:"rpc_port"`
+	JumpHosts  []JumpHost `json:"jump_hosts,omitempty"`
+	// keepalive interval in seconds
+	KeepAliveInterval int `json:"keepalive_interval"`
+	KeepAliveMisses   int `json:"keepalive_misses"`
+}
+
+// JumpHost is a single hop to the host in "user@host:port" format.
+// Like for the profile itself no secrets are kept, password or passphrase is asked on connect
+type JumpHost struct {
+	Spec       string     `json:"spec"`
+	AuthMethod AuthMethod `json:"auth_method"`
+	KeyPath    string     `json:"key_path,omitempty"`
+}
+
+// fills empty ports with default values
+func (p *Profile) ApplyDefaults() {
+	if p.Port == 0 {
+		p.Port = types.DEFAULT_SSH_PORT
+	}
+	if p.ShidaiPort == 0 {
+		p.ShidaiPort = types.DEFAULT_SHIDAI_PORT
+	}
+	if p.InterxPort == 0 {
+		p.InterxPort = types.DEFAULT_INTERX_PORT
+	}
+	if p.RPCPort == 0 {
+		p.RPCPort = types.DEFAULT_RPC_PORT
+	}
+	if p.AuthMethod == "" {
+		p.AuthMethod = AuthPassword
+	}
+	if p.KeepAliveInterval == 0 {
+		p.KeepAliveInterval = types.DEFAULT_KEEPALIVE_INTERVAL
+	}
+	if p.KeepAliveMisses == 0 {
+		p.KeepAliveMisses = types.DEFAULT_KEEPALIVE_MISSES
+	}
+}
+
+func (p *Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if strings.TrimSpace(p.Host) == "" {
+		return fmt.Errorf("host cannot be empty")
+	}
+	if strings.TrimSpace(p.User) == "" {
+		return fmt.Errorf("user cannot be empty")
+	}
+	// checked in fixed order so the same profile always reports the same error
+	ports := []struct {
+		name string
+		port int
+	}{{"ssh", p.Port}, {"shidai", p.ShidaiPort}, {"interx", p.InterxPort}, {"rpc", p.RPCPort}}
+	for _, port := range ports {
+		if port.port < 1 || port.port > 65535 {
+			return fmt.Errorf("%v port <%v> is not valid", port.name, port.port)
+		}
+	}
+	if p.KeepAliveInterval < 1 {
+		return fmt.Errorf("keepalive interval <%v> is not valid", p.KeepAliveInterval)
+	}
+	if p.KeepAliveMisses < 1 {
+		return fmt.Errorf("keepalive misses <%v> is not valid", p.KeepAliveMisses)
+	}
+	switch p.AuthMethod {
+	case AuthPassword, AuthAgent:
+	case AuthKeyFile:
+		if p.KeyPath == "" {
+			return fmt.Errorf("key path cannot be empty for <%v> auth method", p.AuthMethod)
+		}
+	case AuthCertificate:
+		if p.KeyPath == "" || p.CertPath == "" {
+			return fmt.Errorf("key and certificate paths cannot be empty for <%v> auth method", p.AuthMethod)
+		}
+	default:
+		return fmt.Errorf("unknown auth method <%v>", p.AuthMethod)
+	}
+	for i, j := range p.JumpHosts {
+		if strings.TrimSpace(j.Spec) == "" {
+			return fmt.Errorf("jump host %v cannot be empty", i+1)
+		}
+		switch j.AuthMethod {
+		case AuthPassword, AuthAgent:
+		case AuthKeyFile:
+			if j.KeyPath == "" {
+				return fmt.Errorf("key path cannot be empty for jump host <%v>", j.Spec)
+			}
+		default:
+			return fmt.Errorf("unknown auth method <%v> for jump host <%v>", j.AuthMethod, j.Spec)
+		}
+	}
+	return nil
+}
+
+// returns "host:port" string for ssh dial
+func (p *Profile) Address() string {
+	return net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+}
+
+// Store keeps profiles in json file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// returns all profiles sorted by name, missing file is treated as empty store
+func (s *Store) Load() ([]Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *Store) Get(name string) (Profile, error) {
+	list, err := s.Load()
+	if err != nil {
+		return Profile{}, err
+	}
+	for _, p := range list {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("<%v>: %w", name, ErrProfileNotFound)
+}
+
+// creates new profile or overwrites existing one with the same name
+func (s *Store) Save(p Profile) error {
+	p.ApplyDefaults()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.save(list, p)
+}
+
+// replaces or appends p to list and writes it, caller holds the lock
+func (s *Store) save(list []Profile, p Profile) error {
+	replaced := false
+	for i := range list {
+		if list[i].Name == p.Name {
+			list[i] = p
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		list = append(list, p)
+	}
+	return s.write(list)
+}
+
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].Name == name {
+			return s.write(append(list[:i], list[i+1:]...))
+		}
+	}
+	return fmt.Errorf("<%v>: %w", name, ErrProfileNotFound)
+}
+
+// copies profile under the first free "<name> (copy N)" name, lookup and save are done
+// under one lock so concurrent duplicates do not pick the same name
+func (s *Store) Duplicate(name string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.load()
+	if err != nil {
+		return Profile{}, err
+	}
+	var p Profile
+	found := false
+	taken := make(map[string]bool, len(list))
+	for _, l := range list {
+		taken[l.Name] = true
+		if l.Name == name {
+			p, found = l, true
+		}
+	}
+	if !found {
+		return Profile{}, fmt.Errorf("<%v>: %w", name, ErrProfileNotFound)
+	}
+	for i := 1; ; i++ {
+		newName := fmt.Sprintf("%v (copy %v)", name, i)
+		if !taken[newName] {
+			p.Name = newName
+			break
+		}
+	}
+	p.ApplyDefaults()
+	if err = p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	return p, s.save(list, p)
+}
+
+func (s *Store) load() ([]Profile, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Profile{}, nil
+		}
+		return nil, fmt.Errorf("unable to read profiles: %w", err)
+	}
+	var list []Profile
+	err = json.Unmarshal(b, &list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse profiles file <%v>: %w", s.path, err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+func (s *Store) write(list []Profile) error {
+	b, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(s.path, b, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write profiles: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package shidaiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/httph"
+	"github.com/KiraCore/kensho/types"
+	shidaiendpoint "github.com/KiraCore/kensho/types/endpoint/shidai"
+	"golang.org/x/crypto/ssh"
+)
+
+// deadline for execute endpoint when ctx has none, commands like join or start can take several minutes
+const ExecuteTimeout = 10 * time.Minute
+
+type LogComponent string
+
+const (
+	SekaiLogs  LogComponent = "sekai"
+	InterxLogs LogComponent = "interx"
+	ShidaiLogs LogComponent = "shidai"
+)
+
+// execute endpoint commands
+const (
+	JoinCommand   = "join"
+	StartCommand  = "start"
+	StopCommand   = "stop"
+	TxCommand     = "tx"
+	SekaidCommand = "sekaid"
+
+	// path of sekaid binary inside sekai container
+	sekaidBinary = "/sekaid"
+)
+
+// Client is the shidai API of a single node
+type Client interface {
+	Status(ctx context.Context) (shidaiendpoint.Status, error)
+	Validator(ctx context.Context) (*shidaiendpoint.Validator, error)
+	Dashboard(ctx context.Context) (*shidaiendpoint.Dashboard, error)
+	// returns toml file as string
+	Config(ctx context.Context, configType shidaiendpoint.ConfigType) (string, error)
+	SetConfig(ctx context.Context, configType shidaiendpoint.ConfigType, toml string) error
+	// opens server sent events stream of component logs, stream is closed by closing returned reader or cancelling ctx
+	Logs(ctx context.Context, component LogComponent) (io.ReadCloser, error)
+
+	Join(ctx context.Context, args types.Args) (ExecuteResponse, error)
+	Start(ctx context.Context) (ExecuteResponse, error)
+	Stop(ctx context.Context) (ExecuteResponse, error)
+	Tx(ctx context.Context, args types.ExecSekaiMaintenanceCommands) (ExecuteResponse, error)
+	// runs sekaid with args on the node, first arg is the sekaid subcommand
+	Sekaid(ctx context.Context, args []string) (ExecuteResponse, error)
+}
+
+// sekin returns output of executed command as is
+type ExecuteResponse struct {
+	Output string
+}
+
+var ErrInvalidPort = errors.New("invalid shidai port")
+
+// non-2xx response of shidai
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("shidai %v %v failed with status %d: %v", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// TunnelClient talks to shidai listening on localhost of the node through pooled ssh tunnel.
+// Requests without ctx deadline get httph.DefaultRequestTimeout (ExecuteTimeout for execute endpoint)
+type TunnelClient struct {
+	sshClient *ssh.Client
+	port      int
+}
+
+var _ Client = (*TunnelClient)(nil)
+
+func New(sshClient *ssh.Client, port int) *TunnelClient {
+	return &TunnelClient{sshClient: sshClient, port: port}
+}
+
+func (c *TunnelClient) Status(ctx context.Context) (shidaiendpoint.Status, error) {
+	var data shidaiendpoint.Status
+	err := c.getJSON(ctx, "/status", &data)
+	return data, err
+}
+
+func (c *TunnelClient) Validator(ctx context.Context) (*shidaiendpoint.Validator, error) {
+	var data shidaiendpoint.Validator
+	if err := c.getJSON(ctx, "/validator", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *TunnelClient) Dashboard(ctx context.Context) (*shidaiendpoint.Dashboard, error) {
+	var data shidaiendpoint.Dashboard
+	if err := c.getJSON(ctx, "/dashboard", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *TunnelClient) Config(ctx context.Context, configType shidaiendpoint.ConfigType) (string, error) {
+	// shidai reads config with POST, body selects the file
+	out, err := c.do(ctx, http.MethodPost, "/config", shidaiendpoint.ConfigRequest{Type: configType}, httph.DefaultRequestTimeout)
+	if err != nil {
+		return "", fmt.Errorf("unable to get <%v>: %w", configType, err)
+	}
+	return string(out), nil
+}
+
+func (c *TunnelClient) SetConfig(ctx context.Context, configType shidaiendpoint.ConfigType, toml string) error {
+	_, err := c.do(ctx, http.MethodPut, "/config", shidaiendpoint.ConfigRequest{Type: configType, TomlData: toml}, httph.DefaultRequestTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to set <%v>: %w", configType, err)
+	}
+	return nil
+}
+
+func (c *TunnelClient) Logs(ctx context.Context, component LogComponent) (io.ReadCloser, error) {
+	address, err := c.url("/logs/" + string(component))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := httph.TunnelClient(c.sshClient).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open <%v> logs: %w", component, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &APIError{Method: http.MethodGet, Path: req.URL.Path, StatusCode: resp.StatusCode, Message: string(body)}
+	}
+	return resp.Body, nil
+}
+
+func (c *TunnelClient) Join(ctx context.Context, args types.Args) (ExecuteResponse, error) {
+	return c.execute(ctx, types.RequestDeployPayload{Command: JoinCommand, Args: args})
+}
+
+func (c *TunnelClient) Start(ctx context.Context) (ExecuteResponse, error) {
+	return c.execute(ctx, types.RequestDeployPayload{Command: StartCommand})
+}
+
+func (c *TunnelClient) Stop(ctx context.Context) (ExecuteResponse, error) {
+	return c.execute(ctx, types.RequestDeployPayload{Command: StopCommand})
+}
+
+func (c *TunnelClient) Tx(ctx context.Context, args types.ExecSekaiMaintenanceCommands) (ExecuteResponse, error) {
+	return c.execute(ctx, types.RequestTXPayload{Command: TxCommand, Args: args})
+}
+
+func (c *TunnelClient) Sekaid(ctx context.Context, args []string) (ExecuteResponse, error) {
+	return c.execute(ctx, types.ExecSekaiCommands{Command: SekaidCommand, ExecArgs: types.ExecArgs{Exec: append([]string{sekaidBinary}, args...)}})
+}
+
+func (c *TunnelClient) execute(ctx context.Context, payload any) (ExecuteResponse, error) {
+	out, err := c.do(ctx, http.MethodPost, "/api/execute", payload, ExecuteTimeout)
+	if err != nil {
+		return ExecuteResponse{}, err
+	}
+	return ExecuteResponse{Output: string(out)}, nil
+}
+
+func (c *TunnelClient) getJSON(ctx context.Context, path string, data any) error {
+	out, err := c.do(ctx, http.MethodGet, path, nil, httph.DefaultRequestTimeout)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(out, data); err != nil {
+		return fmt.Errorf("unable to decode <%v> response <%v>: %w", path, string(out), err)
+	}
+	return nil
+}
+
+// sends payload as json, defaultTimeout is applied when ctx has no deadline
+func (c *TunnelClient) do(ctx context.Context, method, path string, payload any, defaultTimeout time.Duration) ([]byte, error) {
+	address, err := c.url(path)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if payload != nil {
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+	}
+
+	out, err := httph.ExecHttpRequestBySSHTunnelWithContext(ctx, c.sshClient, address, method, body)
+	var statusErr *httph.StatusError
+	if errors.As(err, &statusErr) {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: statusErr.StatusCode, Message: string(statusErr.Body)}
+		log.Println(apiErr)
+		return nil, apiErr
+	}
+	return out, err
+}
+
+func (c *TunnelClient) url(path string) (string, error) {
+	if !httph.ValidatePortRange(strconv.Itoa(c.port)) {
+		return "", fmt.Errorf("%w <%v>", ErrInvalidPort, c.port)
+	}
+	return fmt.Sprintf("http://localhost:%v%v", c.port, path), nil
+}
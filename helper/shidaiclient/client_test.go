@@ -0,0 +1,241 @@
+package shidaiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/KiraCore/kensho/helper/gssh/sshtest"
+	"github.com/KiraCore/kensho/types"
+	shidaiendpoint "github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// fake shidai behind httptest server reached through ssh tunnel, handler responses are keyed by "METHOD /path"
+type testShidai struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	handlers map[string]http.HandlerFunc
+}
+
+func newTestClient(t *testing.T, handlers map[string]http.HandlerFunc) (*TunnelClient, *testShidai) {
+	t.Helper()
+	s := &testShidai{handlers: handlers}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, recordedRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+		s.mu.Unlock()
+		if h, ok := s.handlers[r.Method+" "+r.URL.Path]; ok {
+			h(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return New(sshtest.NewClient(t), p), s
+}
+
+func (s *testShidai) last(t *testing.T) recordedRequest {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		t.Fatal("no request received")
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func TestTunnelClientGet(t *testing.T) {
+	c, s := newTestClient(t, map[string]http.HandlerFunc{
+		"GET /status":    respond(200, `{"sekai":{"version":"v0.4.0","infra":true},"syslog-ng":{"version":"4.1","infra":true}}`),
+		"GET /dashboard": respond(200, `{"val_status":"ACTIVE","blocks":"42"}`),
+	})
+	ctx := context.Background()
+
+	status, err := c.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Sekai.Version != "v0.4.0" || !status.Sekai.Infra || status.Syslog.Version != "4.1" || status.Interx.Infra {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if r := s.last(t); r.Method != http.MethodGet || r.Path != "/status" {
+		t.Fatalf("unexpected request %+v", r)
+	}
+
+	d, err := c.Dashboard(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ValidatorStatus != "ACTIVE" || d.Blocks != "42" {
+		t.Fatalf("unexpected dashboard %+v", d)
+	}
+}
+
+func TestTunnelClientConfig(t *testing.T) {
+	c, s := newTestClient(t, map[string]http.HandlerFunc{
+		"POST /config": respond(200, "moniker = \"kira\"\n"),
+		"PUT /config":  respond(200, ""),
+	})
+	ctx := context.Background()
+
+	toml, err := c.Config(ctx, shidaiendpoint.AppToml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toml != "moniker = \"kira\"\n" {
+		t.Fatalf("unexpected config %q", toml)
+	}
+	var req shidaiendpoint.ConfigRequest
+	if err = json.Unmarshal([]byte(s.last(t).Body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Type != shidaiendpoint.AppToml || req.TomlData != "" {
+		t.Fatalf("unexpected config request %+v", req)
+	}
+
+	if err = c.SetConfig(ctx, shidaiendpoint.ConfigToml, "a = 1"); err != nil {
+		t.Fatal(err)
+	}
+	r := s.last(t)
+	if err = json.Unmarshal([]byte(r.Body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if r.Method != http.MethodPut || req.Type != shidaiendpoint.ConfigToml || req.TomlData != "a = 1" {
+		t.Fatalf("unexpected request %+v, body %+v", r, req)
+	}
+}
+
+func TestTunnelClientExecutePayloads(t *testing.T) {
+	c, s := newTestClient(t, map[string]http.HandlerFunc{
+		"POST /api/execute": respond(200, "ok"),
+	})
+	ctx := context.Background()
+	args := types.Args{IP: "1.2.3.4", InterxPort: 11000, RPCPort: 26657, P2PPort: 26656, Mnemonic: "word word", Local: true}
+
+	tests := []struct {
+		name string
+		call func() (ExecuteResponse, error)
+		want any
+	}{
+		{"join", func() (ExecuteResponse, error) { return c.Join(ctx, args) },
+			types.RequestDeployPayload{Command: JoinCommand, Args: args}},
+		{"start", func() (ExecuteResponse, error) { return c.Start(ctx) },
+			types.RequestDeployPayload{Command: StartCommand}},
+		{"tx", func() (ExecuteResponse, error) {
+			return c.Tx(ctx, types.ExecSekaiMaintenanceCommands{TX: types.ClaimValidatorSeat, Moniker: "kira"})
+		}, types.RequestTXPayload{Command: TxCommand, Args: types.ExecSekaiMaintenanceCommands{TX: types.ClaimValidatorSeat, Moniker: "kira"}}},
+		{"sekaid", func() (ExecuteResponse, error) { return c.Sekaid(ctx, []string{"version"}) },
+			types.ExecSekaiCommands{Command: SekaidCommand, ExecArgs: types.ExecArgs{Exec: []string{"/sekaid", "version"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Output != "ok" {
+				t.Fatalf("unexpected output %q", out.Output)
+			}
+			r := s.last(t)
+			if r.Method != http.MethodPost || r.Path != "/api/execute" {
+				t.Fatalf("unexpected request %+v", r)
+			}
+			// decode into the type of expected payload and compare
+			got := reflect.New(reflect.TypeOf(tt.want))
+			if err = json.Unmarshal([]byte(r.Body), got.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Elem().Interface(), tt.want) {
+				t.Fatalf("payload %+v, want %+v", got.Elem().Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTunnelClientErrors(t *testing.T) {
+	c, _ := newTestClient(t, map[string]http.HandlerFunc{
+		"GET /validator":    respond(500, "validator not found"),
+		"GET /dashboard":    respond(200, "not json"),
+		"POST /api/execute": respond(400, "unknown command"),
+	})
+	ctx := context.Background()
+
+	_, err := c.Validator(ctx)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Path != "/validator" || apiErr.StatusCode != 500 || apiErr.Message != "validator not found" {
+		t.Fatalf("unexpected APIError %+v", apiErr)
+	}
+
+	_, err = c.Stop(ctx)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 || apiErr.Path != "/api/execute" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err = c.Dashboard(ctx); err == nil || errors.As(err, &apiErr) {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	if _, err = New(c.sshClient, 0).Status(ctx); !errors.Is(err, ErrInvalidPort) {
+		t.Fatalf("expected ErrInvalidPort, got %v", err)
+	}
+}
+
+func TestTunnelClientLogs(t *testing.T) {
+	c, _ := newTestClient(t, map[string]http.HandlerFunc{
+		"GET /logs/sekai": func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "text/event-stream" {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			io.WriteString(w, "data: block 1\n\n")
+		},
+	})
+	ctx := context.Background()
+
+	rc, err := c.Logs(ctx, SekaiLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(b) != "data: block 1\n\n" {
+		t.Fatalf("unexpected logs %q, %v", b, err)
+	}
+
+	_, err = c.Logs(ctx, InterxLogs)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Path != "/logs/interx" {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package shidaiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/KiraCore/kensho/types"
+	shidaiendpoint "github.com/KiraCore/kensho/types/endpoint/shidai"
+)
+
+// Fake is in-memory Client for tests, it returns configured responses and records execute requests.
+// Response fields should be set before the fake is shared between goroutines
+type Fake struct {
+	StatusResponse    shidaiendpoint.Status
+	ValidatorResponse shidaiendpoint.Validator
+	DashboardResponse shidaiendpoint.Dashboard
+	// log stream content per component
+	LogResponses  map[LogComponent]string
+	ExecuteOutput string
+	// returned by every call when set
+	Err error
+
+	mu      sync.Mutex
+	configs map[shidaiendpoint.ConfigType]string
+	// payloads sent to execute endpoint, in order
+	executed []any
+}
+
+var _ Client = (*Fake)(nil)
+
+func NewFake() *Fake {
+	return &Fake{
+		LogResponses: map[LogComponent]string{},
+		configs:      map[shidaiendpoint.ConfigType]string{},
+	}
+}
+
+// returns copy of execute payloads received so far
+func (f *Fake) Executed() []any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]any(nil), f.executed...)
+}
+
+func (f *Fake) Status(ctx context.Context) (shidaiendpoint.Status, error) {
+	if f.Err != nil {
+		return shidaiendpoint.Status{}, f.Err
+	}
+	return f.StatusResponse, nil
+}
+
+func (f *Fake) Validator(ctx context.Context) (*shidaiendpoint.Validator, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	v := f.ValidatorResponse
+	return &v, nil
+}
+
+func (f *Fake) Dashboard(ctx context.Context) (*shidaiendpoint.Dashboard, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	d := f.DashboardResponse
+	return &d, nil
+}
+
+func (f *Fake) Config(ctx context.Context, configType shidaiendpoint.ConfigType) (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.configs[configType], nil
+}
+
+func (f *Fake) SetConfig(ctx context.Context, configType shidaiendpoint.ConfigType, toml string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs[configType] = toml
+	return nil
+}
+
+func (f *Fake) Logs(ctx context.Context, component LogComponent) (io.ReadCloser, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	logs, ok := f.LogResponses[component]
+	if !ok {
+		return nil, &APIError{Method: "GET", Path: fmt.Sprintf("/logs/%v", component), StatusCode: 404, Message: "not found"}
+	}
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+func (f *Fake) Join(ctx context.Context, args types.Args) (ExecuteResponse, error) {
+	return f.execute(types.RequestDeployPayload{Command: JoinCommand, Args: args})
+}
+
+func (f *Fake) Start(ctx context.Context) (ExecuteResponse, error) {
+	return f.execute(types.RequestDeployPayload{Command: StartCommand})
+}
+
+func (f *Fake) Stop(ctx context.Context) (ExecuteResponse, error) {
+	return f.execute(types.RequestDeployPayload{Command: StopCommand})
+}
+
+func (f *Fake) Tx(ctx context.Context, args types.ExecSekaiMaintenanceCommands) (ExecuteResponse, error) {
+	return f.execute(types.RequestTXPayload{Command: TxCommand, Args: args})
+}
+
+func (f *Fake) Sekaid(ctx context.Context, args []string) (ExecuteResponse, error) {
+	return f.execute(types.ExecSekaiCommands{Command: SekaidCommand, ExecArgs: types.ExecArgs{Exec: append([]string{sekaidBinary}, args...)}})
+}
+
+func (f *Fake) execute(payload any) (ExecuteResponse, error) {
+	f.mu.Lock()
+	f.executed = append(f.executed, payload)
+	f.mu.Unlock()
+	if f.Err != nil {
+		return ExecuteResponse{}, f.Err
+	}
+	return ExecuteResponse{Output: f.ExecuteOutput}, nil
+}
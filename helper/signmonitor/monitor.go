@@ -0,0 +1,219 @@
+package signmonitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KiraCore/kensho/helper/cometrpc"
+	sekaiendpoint "github.com/KiraCore/kensho/types/endpoint/sekai"
+)
+
+const (
+	// alerts are not raised until window has at least this many blocks
+	minBlocksForAlert = 20
+	// validator voting power is checked again after this many blocks
+	statusRefreshBlocks = 50
+	statusRetryDelay    = 10 * time.Second
+)
+
+// raised when miss rate crosses the threshold, and again with Resolved once it drops below
+type Alert struct {
+	Address   string
+	Missed    int
+	Total     int
+	MissRate  float64
+	Threshold float64
+	Resolved  bool
+}
+
+func (a Alert) String() string {
+	if a.Resolved {
+		return fmt.Sprintf("validator %v miss rate back to %.1f%% (%v/%v blocks)", a.Address, a.MissRate*100, a.Missed, a.Total)
+	}
+	return fmt.Sprintf("validator %v missed %v of last %v blocks (%.1f%%, threshold %.1f%%)", a.Address, a.Missed, a.Total, a.MissRate*100, a.Threshold*100)
+}
+
+// Monitor follows new blocks of the node and records whether the node's validator signed each of them
+type Monitor struct {
+	rpc    *cometrpc.Client
+	Window *Window
+	// called from monitor goroutine, must be set before Run
+	OnAlert func(Alert)
+
+	mu        sync.Mutex
+	threshold float64
+	address   string
+	// validator is in active set, blocks are not recorded otherwise
+	inSet    bool
+	alerting bool
+	err      error
+}
+
+// threshold is miss rate between 0 and 1
+func NewMonitor(rpc *cometrpc.Client, windowSize int, threshold float64) *Monitor {
+	return &Monitor{rpc: rpc, Window: NewWindow(windowSize), threshold: threshold}
+}
+
+// hex consensus address of the validator, empty until node status is known
+func (m *Monitor) Address() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.address
+}
+
+// returns false when node's validator has no voting power
+func (m *Monitor) InActiveSet() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inSet
+}
+
+func (m *Monitor) Threshold() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.threshold
+}
+
+func (m *Monitor) SetThreshold(threshold float64) {
+	m.mu.Lock()
+	m.threshold = threshold
+	m.mu.Unlock()
+	m.checkThreshold()
+}
+
+// last rpc error, nil while subscription works
+func (m *Monitor) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+func (m *Monitor) setErr(err error) {
+	m.mu.Lock()
+	m.err = err
+	m.mu.Unlock()
+}
+
+// Run follows blocks until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	if err := m.refreshStatus(ctx); err != nil {
+		return
+	}
+	go m.backfill(ctx)
+
+	events := m.rpc.Subscribe(ctx, []string{cometrpc.NewBlockQuery}, m.setErr)
+	blocks := 0
+	for ev := range events {
+		data, err := ev.NewBlock()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if blocks++; blocks%statusRefreshBlocks == 0 {
+			m.refreshStatus(ctx)
+		}
+		m.record(data.Block.LastCommit, data.Block.Header.Time)
+	}
+}
+
+// reads validator address and voting power of the node, retries until it succeeds or ctx is done
+func (m *Monitor) refreshStatus(ctx context.Context) error {
+	for {
+		status, err := m.rpc.Status(ctx)
+		if err == nil {
+			info := status.Result.ValidatorInfo
+			m.mu.Lock()
+			m.address = info.Address
+			m.inSet = info.VotingPower != "" && info.VotingPower != "0"
+			m.mu.Unlock()
+			return nil
+		}
+		m.setErr(err)
+		log.Printf("Signing monitor unable to get node status, retrying in %v: %v", statusRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(statusRetryDelay):
+		}
+	}
+}
+
+// fills the window with commits of blocks before the monitor was started
+func (m *Monitor) backfill(ctx context.Context) {
+	latest, err := m.rpc.Commit(ctx, 0)
+	if err != nil {
+		log.Printf("Signing monitor backfill failed: %v", err)
+		return
+	}
+	top, err := strconv.ParseInt(latest.SignedHeader.Header.Height, 10, 64)
+	if err != nil {
+		return
+	}
+	for h := top; h > 0 && h > top-int64(m.Window.Size()); h-- {
+		if m.Window.Has(h) {
+			continue
+		}
+		c, err := m.rpc.Commit(ctx, h)
+		if err != nil {
+			log.Printf("Signing monitor backfill stopped at height %v: %v", h, err)
+			return
+		}
+		m.record(c.SignedHeader.Commit, c.SignedHeader.Header.Time)
+	}
+}
+
+func (m *Monitor) record(commit sekaiendpoint.Commit, t time.Time) {
+	height, err := strconv.ParseInt(commit.Height, 10, 64)
+	if err != nil || height == 0 {
+		return
+	}
+	m.mu.Lock()
+	address, inSet := m.address, m.inSet
+	m.mu.Unlock()
+	if !inSet {
+		return
+	}
+	m.Window.Add(BlockSign{Height: height, Signed: SignedBy(commit, address), Time: t})
+	m.checkThreshold()
+}
+
+// raises alert when miss rate crosses the threshold in either direction
+func (m *Monitor) checkThreshold() {
+	missed, total, rate := m.Window.MissRate()
+	if total < min(minBlocksForAlert, m.Window.Size()) {
+		return
+	}
+	m.mu.Lock()
+	alert := Alert{Address: m.address, Missed: missed, Total: total, MissRate: rate, Threshold: m.threshold}
+	switch {
+	case rate >= m.threshold && !m.alerting:
+		m.alerting = true
+	case rate < m.threshold && m.alerting:
+		m.alerting = false
+		alert.Resolved = true
+	default:
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	log.Printf("Signing monitor: %v", alert)
+	if m.OnAlert != nil {
+		m.OnAlert(alert)
+	}
+}
+
+// returns true when commit has signature of address for the committed block
+func SignedBy(commit sekaiendpoint.Commit, address string) bool {
+	for _, s := range commit.Signatures {
+		if strings.EqualFold(s.ValidatorAddress, address) {
+			return s.BlockIDFlag == sekaiendpoint.BlockIDFlagCommit
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package signmonitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// signing result of our validator for a single block
+type BlockSign struct {
+	Height int64
+	Signed bool
+	Time   time.Time
+}
+
+// Window keeps signing results of the last `size` blocks ordered by height
+type Window struct {
+	mu     sync.Mutex
+	size   int
+	blocks []BlockSign
+}
+
+func NewWindow(size int) *Window {
+	if size < 1 {
+		size = 1
+	}
+	return &Window{size: size}
+}
+
+func (w *Window) Size() int {
+	return w.size
+}
+
+// adds block, blocks can come out of order (backfill runs next to live events), duplicates are replaced
+func (w *Window) Add(b BlockSign) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	i := sort.Search(len(w.blocks), func(i int) bool { return w.blocks[i].Height >= b.Height })
+	if i < len(w.blocks) && w.blocks[i].Height == b.Height {
+		w.blocks[i] = b
+		return
+	}
+	w.blocks = append(w.blocks, BlockSign{})
+	copy(w.blocks[i+1:], w.blocks[i:])
+	w.blocks[i] = b
+	if len(w.blocks) > w.size {
+		w.blocks = w.blocks[len(w.blocks)-w.size:]
+	}
+}
+
+// returns copy of blocks, oldest first
+func (w *Window) Blocks() []BlockSign {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]BlockSign(nil), w.blocks...)
+}
+
+// returns true when block at height is already in the window
+func (w *Window) Has(height int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	i := sort.Search(len(w.blocks), func(i int) bool { return w.blocks[i].Height >= height })
+	return i < len(w.blocks) && w.blocks[i].Height == height
+}
+
+// returns number of missed and all blocks in the window, rate is 0 for empty window
+func (w *Window) MissRate() (missed, total int, rate float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range w.blocks {
+		if !b.Signed {
+			missed++
+		}
+	}
+	total = len(w.blocks)
+	if total > 0 {
+		rate = float64(missed) / float64(total)
+	}
+	return missed, total, rate
+}
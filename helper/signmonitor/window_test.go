@@ -0,0 +1,88 @@
+package signmonitor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        int
+		add         []BlockSign
+		wantHeights []int64
+		wantMissed  int
+		wantRate    float64
+	}{
+		{
+			name:        "empty",
+			size:        3,
+			wantHeights: nil,
+		},
+		{
+			name:        "ordered by height",
+			size:        5,
+			add:         []BlockSign{{Height: 3, Signed: true}, {Height: 1}, {Height: 2, Signed: true}},
+			wantHeights: []int64{1, 2, 3},
+			wantMissed:  1,
+			wantRate:    1.0 / 3,
+		},
+		{
+			name:        "duplicate replaces block",
+			size:        5,
+			add:         []BlockSign{{Height: 1}, {Height: 2}, {Height: 1, Signed: true}},
+			wantHeights: []int64{1, 2},
+			wantMissed:  1,
+			wantRate:    0.5,
+		},
+		{
+			name:        "oldest blocks are dropped",
+			size:        2,
+			add:         []BlockSign{{Height: 1}, {Height: 2, Signed: true}, {Height: 3, Signed: true}},
+			wantHeights: []int64{2, 3},
+		},
+		{
+			name:        "backfilled block older than window is dropped",
+			size:        2,
+			add:         []BlockSign{{Height: 5, Signed: true}, {Height: 6}, {Height: 1}},
+			wantHeights: []int64{5, 6},
+			wantMissed:  1,
+			wantRate:    0.5,
+		},
+		{
+			name:        "size is at least one",
+			size:        0,
+			add:         []BlockSign{{Height: 1}, {Height: 2}},
+			wantHeights: []int64{2},
+			wantMissed:  1,
+			wantRate:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWindow(tt.size)
+			for _, b := range tt.add {
+				w.Add(b)
+			}
+			var heights []int64
+			for _, b := range w.Blocks() {
+				heights = append(heights, b.Height)
+			}
+			if !reflect.DeepEqual(heights, tt.wantHeights) {
+				t.Fatalf("heights %v, want %v", heights, tt.wantHeights)
+			}
+			for _, h := range tt.wantHeights {
+				if !w.Has(h) {
+					t.Errorf("Has(%v) = false", h)
+				}
+			}
+			if w.Has(100) {
+				t.Error("Has(100) = true")
+			}
+			missed, total, rate := w.MissRate()
+			if missed != tt.wantMissed || total != len(tt.wantHeights) || rate != tt.wantRate {
+				t.Fatalf("MissRate() = %v, %v, %v, want %v, %v, %v", missed, total, rate, tt.wantMissed, len(tt.wantHeights), tt.wantRate)
+			}
+		})
+	}
+}
@@ -1,17 +1,63 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"github.com/KiraCore/kensho/cli"
 	"github.com/KiraCore/kensho/gui"
+	"github.com/KiraCore/kensho/helper/alerting"
+	"github.com/KiraCore/kensho/helper/exporter"
+	"github.com/KiraCore/kensho/helper/gssh"
+	"github.com/KiraCore/kensho/helper/metrics"
+	"github.com/KiraCore/kensho/helper/profiles"
+	"github.com/KiraCore/kensho/utils"
 )
 
 func main() {
 	devMode := flag.Bool("dev", false, "Enable developer mode")
+	metricsListen := flag.String("metrics-listen", "", "Serve prometheus metrics of connected hosts on this address, e.g. 127.0.0.1:9110")
 	flag.Parse()
 
+	homeFolder, err := utils.GetKenshoDataFolder()
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	knownHosts, err := gssh.NewKnownHosts(filepath.Join(homeFolder, "known_hosts"))
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	profileStore := profiles.NewStore(filepath.Join(homeFolder, "profiles.json"))
+
+	// headless mode, commands print their results to stdout so logs are written to stderr only with -dev
+	if flag.NArg() > 0 {
+		if !*devMode {
+			log.SetOutput(io.Discard)
+		}
+		os.Exit(cli.Run(&cli.Env{
+			HomeFolder: homeFolder,
+			KnownHosts: knownHosts,
+			Profiles:   profileStore,
+			Stdin:      os.Stdin,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		}, flag.Args()))
+	}
+
+	var metricsExporter *exporter.Exporter
+	if *metricsListen != "" {
+		metricsExporter = exporter.New()
+		if err = metricsExporter.Serve(context.Background(), *metricsListen); err != nil {
+			log.Fatalf("ERROR: %v", err)
+		}
+	}
+
 	a := app.NewWithID("Kensho")
 	w := a.NewWindow("Kensho")
 	w.SetMaster()
@@ -20,6 +66,12 @@ func main() {
 		DeveloperMode: *devMode,
 		Window:        w,
 		Version:       a.Metadata().Version,
+		HomeFolder:    homeFolder,
+		KnownHosts:    knownHosts,
+		Profiles:      profileStore,
+		AlertConfig:   alerting.NewConfigStore(filepath.Join(homeFolder, "alerting.json")),
+		Metrics:       metrics.NewStore(filepath.Join(homeFolder, "metrics"), metrics.DefaultRetention),
+		Exporter:      metricsExporter,
 	}
 	g.WaitDialog = gui.NewWaitDialog(&g)
 	content := g.MakeGui()
@@ -0,0 +1,6 @@
+package interx
+
+type Balances struct {
+	Balances   []Coin     `json:"balances"`
+	Pagination Pagination `json:"pagination"`
+}
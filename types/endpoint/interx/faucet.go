@@ -0,0 +1,10 @@
+package interx
+
+type FaucetInfo struct {
+	Address  string `json:"address"`
+	Balances []Coin `json:"balances"`
+}
+
+type FaucetClaim struct {
+	Hash string `json:"hash"`
+}
@@ -0,0 +1,14 @@
+package interx
+
+type IdentityRecord struct {
+	ID        string   `json:"id"`
+	Address   string   `json:"address"`
+	Key       string   `json:"key"`
+	Value     string   `json:"value"`
+	Date      string   `json:"date"`
+	Verifiers []string `json:"verifiers"`
+}
+
+type IdentityRecords struct {
+	Records []IdentityRecord `json:"records"`
+}
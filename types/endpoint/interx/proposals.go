@@ -0,0 +1,22 @@
+package interx
+
+import "encoding/json"
+
+type Proposal struct {
+	ProposalID                 string          `json:"proposal_id"`
+	Title                      string          `json:"title"`
+	Description                string          `json:"description"`
+	Content                    json.RawMessage `json:"content"`
+	SubmitTime                 string          `json:"submit_time"`
+	VotingEndTime              string          `json:"voting_end_time"`
+	EnactmentEndTime           string          `json:"enactment_end_time"`
+	MinVotingEndBlockHeight    string          `json:"min_voting_end_block_height"`
+	MinEnactmentEndBlockHeight string          `json:"min_enactment_end_block_height"`
+	ExecResult                 string          `json:"exec_result"`
+	Result                     string          `json:"result"`
+}
+
+type Proposals struct {
+	Proposals  []Proposal `json:"proposals"`
+	Pagination Pagination `json:"pagination"`
+}
@@ -0,0 +1,23 @@
+package interx
+
+type TxTransfer struct {
+	Type    string `json:"type"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Amounts []Coin `json:"amounts"`
+}
+
+type Transaction struct {
+	Time      int64        `json:"time"`
+	Hash      string       `json:"hash"`
+	Status    string       `json:"status"`
+	Direction string       `json:"direction"`
+	Memo      string       `json:"memo"`
+	Fee       []Coin       `json:"fee"`
+	Txs       []TxTransfer `json:"txs"`
+}
+
+type Transactions struct {
+	Transactions []Transaction `json:"transactions"`
+	TotalCount   int           `json:"total_count"`
+}
@@ -4,12 +4,13 @@ import "time"
 
 type Validators struct {
 	Validators []Validator `json:"validators"`
+	Pagination Pagination  `json:"pagination"`
 }
 
 type Validator struct {
 	Top                   string    `json:"top"`
 	Address               string    `json:"address"`
-	Valkey                string    `json: "valkey"`
+	Valkey                string    `json:"valkey"`
 	Pubkey                string    `json:"pubkey"`
 	Proposer              string    `json:"proposer"`
 	Moniker               string    `json:"moniker"`
@@ -0,0 +1,72 @@
+package sekai
+
+import "time"
+
+// commit signature flags
+const (
+	BlockIDFlagAbsent = 1
+	BlockIDFlagCommit = 2
+	BlockIDFlagNil    = 3
+)
+
+type BlockID struct {
+	Hash string `json:"hash"`
+}
+
+type Header struct {
+	ChainID         string    `json:"chain_id"`
+	Height          string    `json:"height"`
+	Time            time.Time `json:"time"`
+	ProposerAddress string    `json:"proposer_address"`
+}
+
+type CommitSig struct {
+	BlockIDFlag      int       `json:"block_id_flag"`
+	ValidatorAddress string    `json:"validator_address"`
+	Timestamp        time.Time `json:"timestamp"`
+	Signature        string    `json:"signature"`
+}
+
+type Commit struct {
+	Height     string      `json:"height"`
+	Round      int         `json:"round"`
+	BlockID    BlockID     `json:"block_id"`
+	Signatures []CommitSig `json:"signatures"`
+}
+
+type Block struct {
+	Header     Header `json:"header"`
+	LastCommit Commit `json:"last_commit"`
+}
+
+// result of /block
+type BlockResult struct {
+	BlockID BlockID `json:"block_id"`
+	Block   Block   `json:"block"`
+}
+
+type SignedHeader struct {
+	Header Header `json:"header"`
+	Commit Commit `json:"commit"`
+}
+
+// result of /commit
+type CommitResult struct {
+	SignedHeader SignedHeader `json:"signed_header"`
+	Canonical    bool         `json:"canonical"`
+}
+
+type Validator struct {
+	Address          string `json:"address"`
+	PubKey           pubKey `json:"pub_key"`
+	VotingPower      string `json:"voting_power"`
+	ProposerPriority string `json:"proposer_priority"`
+}
+
+// result of /validators
+type ValidatorsResult struct {
+	BlockHeight string      `json:"block_height"`
+	Validators  []Validator `json:"validators"`
+	Count       string      `json:"count"`
+	Total       string      `json:"total"`
+}
@@ -0,0 +1,26 @@
+package sekai
+
+// value of NewBlock event
+type EventNewBlock struct {
+	Block   Block   `json:"block"`
+	BlockID BlockID `json:"block_id"`
+}
+
+type TxExecResult struct {
+	Code      int    `json:"code"`
+	Log       string `json:"log"`
+	GasWanted string `json:"gas_wanted"`
+	GasUsed   string `json:"gas_used"`
+}
+
+type TxResult struct {
+	Height string       `json:"height"`
+	Index  int          `json:"index"`
+	Tx     []byte       `json:"tx"`
+	Result TxExecResult `json:"result"`
+}
+
+// value of Tx event
+type EventTx struct {
+	TxResult TxResult `json:"TxResult"`
+}
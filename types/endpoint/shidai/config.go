@@ -0,0 +1,14 @@
+package shidai
+
+type ConfigType string
+
+const (
+	ConfigToml ConfigType = "config_toml"
+	AppToml    ConfigType = "app_toml"
+)
+
+// body of /config request, TomlData is empty when config is requested
+type ConfigRequest struct {
+	Type     ConfigType `json:"type"`
+	TomlData string     `json:"toml_data"`
+}
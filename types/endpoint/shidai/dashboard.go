@@ -0,0 +1,31 @@
+package shidai
+
+type Dashboard struct {
+	RoleIDs []string `json:"role_ids"`
+
+	Date                string `json:"date"`
+	ValidatorStatus     string `json:"val_status"`
+	Blocks              string `json:"blocks"`
+	Top                 string `json:"top"`
+	Streak              string `json:"streak"`
+	Mischance           string `json:"mischance"`
+	MischanceConfidence string `json:"mischance_confidence"`
+	StartHeight         string `json:"start_height"`
+	LastProducedBlock   string `json:"last_present_block"`
+	ProducedBlocks      string `json:"produced_blocks_counter"`
+	Moniker             string `json:"moniker"`
+	ValidatorAddress    string `json:"address"`
+	ChainID             string `json:"chain_id"`
+	NodeID              string `json:"node_id"`
+	GenesisChecksum     string `json:"genesis_checksum"`
+
+	ActiveValidators   int `json:"active_validators"`
+	PausedValidators   int `json:"paused_validators"`
+	InactiveValidators int `json:"inactive_validators"`
+	JailedValidators   int `json:"jailed_validatore"`
+	WaitingValidators  int `json:"waiting_validators"`
+
+	SeatClaimAvailable bool `json:"seat_claim_available"`
+	Waiting            bool `json:"seat_claim_pending"`
+	CatchingUp         bool `json:"catching_up"`
+}
@@ -1,15 +1,15 @@
 package shidai
 
-type componentInfo struct {
+type ComponentInfo struct {
 	Version string `json:"version"`
 	Infra   bool   `json:"infra"`
 }
 
 type Status struct {
-	Sekai  componentInfo `json:"sekai"`
-	Interx componentInfo `json:"interx"`
-	Shidai componentInfo `json:"shidai"`
-	Syslog componentInfo `json:"syslog-ng"`
+	Sekai  ComponentInfo `json:"sekai"`
+	Interx ComponentInfo `json:"interx"`
+	Shidai ComponentInfo `json:"shidai"`
+	Syslog ComponentInfo `json:"syslog-ng"`
 }
 
 type ValidatorStatus string
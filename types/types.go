@@ -6,11 +6,16 @@ const (
 	SEKIN_STATUS_ENDPOINT  string = "http://localhost:8282/api/status"
 	KIRA_ADDRESS_PREFIX    string = "kira"
 
+	DEFAULT_SSH_PORT    int = 22
 	DEFAULT_INTERX_PORT int = 11000
 	DEFAULT_P2P_PORT    int = 26656
 	DEFAULT_RPC_PORT    int = 26657
 	DEFAULT_GRPC_PORT   int = 9090
 	DEFAULT_SHIDAI_PORT int = 8282
+
+	// ssh keepalive interval in seconds and number of unanswered keepalives before connection is treated as dead
+	DEFAULT_KEEPALIVE_INTERVAL int = 15
+	DEFAULT_KEEPALIVE_MISSES   int = 3
 )
 
 type RequestDeployPayload struct {
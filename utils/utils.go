@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/atotto/clipboard"
 )
@@ -14,3 +16,17 @@ func CopyToClipboard(text string) error {
 	}
 	return nil
 }
+
+// returns folder where Kensho keeps its local data (known_hosts, etc...), creates it if not exist
+func GetKenshoDataFolder() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find user config folder: %w", err)
+	}
+	folder := filepath.Join(configDir, "Kensho")
+	err = os.MkdirAll(folder, 0700)
+	if err != nil {
+		return "", fmt.Errorf("unable to create Kensho data folder: %w", err)
+	}
+	return folder, nil
+}